@@ -0,0 +1,273 @@
+package algo
+
+import (
+	"errors"
+	"time"
+
+	"github.com/quickfixgo/enum"
+	"github.com/shopspring/decimal"
+
+	"github.com/quickfixgo/traderui/oms"
+)
+
+// Strategy is the slicing strategy of an algo
+type Strategy string
+
+// Supported strategies
+const (
+	TWAP    Strategy = "TWAP"
+	VWAP    Strategy = "VWAP"
+	Iceberg Strategy = "ICEBERG"
+)
+
+// State is the lifecycle state of an algo
+type State string
+
+// Algo states
+const (
+	Running   State = "running"
+	Paused    State = "paused"
+	Cancelled State = "cancelled"
+	Done      State = "done"
+	Failed    State = "failed"
+)
+
+// Params are the user supplied algo parameters
+type Params struct {
+	Strategy          Strategy        `json:"strategy"`
+	StartTime         time.Time       `json:"start_time"`
+	EndTime           time.Time       `json:"end_time"`
+	Slices            int             `json:"slices"`
+	Profile           []float64       `json:"profile"`
+	DisplayQty        string          `json:"display_qty"`
+	DisplayQtyDecimal decimal.Decimal `json:"-"`
+	LimitPrice        string          `json:"limit_price"`
+	LimitPriceDecimal decimal.Decimal `json:"-"`
+	// LotSize of the security, child quantities are rounded down to whole lots. Whole units if zero.
+	LotSize decimal.Decimal `json:"-"`
+}
+
+// Init validates the params and initializes computed fields
+func (p *Params) Init(now time.Time) error {
+	var err error
+	if p.LimitPrice != "" {
+		if p.LimitPriceDecimal, err = decimal.NewFromString(p.LimitPrice); err != nil {
+			return errors.New("Invalid LimitPrice")
+		}
+	}
+
+	if p.StartTime.IsZero() {
+		p.StartTime = now
+	}
+
+	switch p.Strategy {
+	case TWAP, VWAP:
+		if p.Strategy == VWAP && len(p.Profile) > 0 {
+			p.Slices = len(p.Profile)
+		}
+
+		if p.Slices <= 0 {
+			return errors.New("Invalid Slices")
+		}
+
+		if !p.EndTime.After(p.StartTime) {
+			return errors.New("Invalid EndTime")
+		}
+
+		for _, w := range p.Profile {
+			if w < 0 {
+				return errors.New("Invalid Profile")
+			}
+		}
+
+	case Iceberg:
+		if p.DisplayQtyDecimal, err = decimal.NewFromString(p.DisplayQty); err != nil || !p.DisplayQtyDecimal.IsPositive() {
+			return errors.New("Invalid DisplayQty")
+		}
+
+		if p.LotSize.IsPositive() && !p.DisplayQtyDecimal.Mod(p.LotSize).IsZero() {
+			return errors.New("DisplayQty is not a multiple of the lot size")
+		}
+
+	default:
+		return errors.New("Unknown Strategy")
+	}
+
+	return nil
+}
+
+// lots rounds qty down to whole lots
+func (p Params) lots(qty decimal.Decimal) decimal.Decimal {
+	if !p.LotSize.IsPositive() {
+		return qty.Floor()
+	}
+
+	return qty.Div(p.LotSize).Floor().Mul(p.LotSize)
+}
+
+// Algo is a parent order worked by the scheduler as a series of child orders
+type Algo struct {
+	ID       int        `json:"id"`
+	State    State      `json:"state"`
+	Params   Params     `json:"params"`
+	Parent   *oms.Order `json:"parent"`
+	Sent     string     `json:"sent"`
+	ChildIDs []int      `json:"child_ids"`
+
+	sent     decimal.Decimal
+	children []*oms.Order
+}
+
+// Active is true if the algo may still send child orders
+func (a *Algo) Active() bool {
+	return a.State == Running || a.State == Paused
+}
+
+// weight returns the share of the parent quantity scheduled in slice i
+func (a *Algo) weight(i int) decimal.Decimal {
+	if a.Params.Strategy != VWAP || len(a.Params.Profile) == 0 {
+		return decimal.NewFromInt(1)
+	}
+
+	return decimal.NewFromFloat(a.Params.Profile[i])
+}
+
+// target returns the cumulative quantity that should have been sent by now
+func (a *Algo) target(now time.Time) decimal.Decimal {
+	p := a.Params
+	if now.Before(p.StartTime) {
+		return decimal.Zero
+	}
+
+	interval := p.EndTime.Sub(p.StartTime) / time.Duration(p.Slices)
+	due := p.Slices
+	if interval > 0 {
+		due = int(now.Sub(p.StartTime)/interval) + 1
+	}
+
+	if due >= p.Slices {
+		return a.Parent.QuantityDecimal
+	}
+
+	total, scheduled := decimal.Zero, decimal.Zero
+	for i := 0; i < p.Slices; i++ {
+		total = total.Add(a.weight(i))
+		if i < due {
+			scheduled = scheduled.Add(a.weight(i))
+		}
+	}
+
+	if total.IsZero() {
+		return decimal.Zero
+	}
+
+	return p.lots(a.Parent.QuantityDecimal.Mul(scheduled).Div(total))
+}
+
+// ended is true if the child order will not fill any further
+func ended(child *oms.Order) bool {
+	switch child.OrdStatus {
+	case enum.OrdStatus_CANCELED, enum.OrdStatus_EXPIRED, enum.OrdStatus_REJECTED:
+		return true
+	}
+
+	return false
+}
+
+// countSent counts the quantity sent in child orders. Children that ended count what they filled,
+// so what the counterparty cancelled or expired is sliced again.
+func (a *Algo) countSent() {
+	sent := decimal.Zero
+	for _, child := range a.children {
+		if ended(child) {
+			cumQty, _ := decimal.NewFromString(child.Closed)
+			sent = sent.Add(cumQty)
+		} else {
+			sent = sent.Add(child.QuantityDecimal)
+		}
+	}
+
+	a.sent = sent
+	a.Sent = sent.String()
+}
+
+// working is true if any child order is still live at the counterparty
+func (a *Algo) working() bool {
+	for _, child := range a.children {
+		if child.Open != "0" && child.OrdStatus != enum.OrdStatus_REJECTED {
+			return true
+		}
+	}
+
+	return false
+}
+
+// due returns the quantity of the next child order, zero if nothing is due
+func (a *Algo) due(now time.Time) decimal.Decimal {
+	remaining := a.Parent.QuantityDecimal.Sub(a.sent)
+	if !remaining.IsPositive() {
+		return decimal.Zero
+	}
+
+	switch a.Params.Strategy {
+	case Iceberg:
+		if now.Before(a.Params.StartTime) || a.working() {
+			return decimal.Zero
+		}
+
+		return decimal.Min(a.Params.DisplayQtyDecimal, remaining)
+	}
+
+	return decimal.Max(a.target(now).Sub(a.sent), decimal.Zero)
+}
+
+// newChild builds a child order for qty, inheriting the instrument and routing from the parent
+func (a *Algo) newChild(qty decimal.Decimal) *oms.Order {
+	child := *a.Parent
+	child.ID = 0
+	child.ClOrdID = ""
+	child.ParentID = a.Parent.ID
+	child.Quantity = qty.String()
+	child.QuantityDecimal = qty
+	child.Closed = ""
+	child.Open = ""
+	child.AvgPx = ""
+	child.OrdStatus = ""
+
+	if a.Params.LimitPrice != "" {
+		child.OrdType = enum.OrdType_LIMIT
+		child.Price = a.Params.LimitPrice
+		child.PriceDecimal = a.Params.LimitPriceDecimal
+	}
+
+	return &child
+}
+
+// rollUp aggregates the fills of the child orders onto the parent
+func (a *Algo) rollUp() {
+	closed, notional, open := decimal.Zero, decimal.Zero, decimal.Zero
+	for _, child := range a.children {
+		cumQty, _ := decimal.NewFromString(child.Closed)
+		avgPx, _ := decimal.NewFromString(child.AvgPx)
+		leavesQty, _ := decimal.NewFromString(child.Open)
+
+		closed = closed.Add(cumQty)
+		notional = notional.Add(cumQty.Mul(avgPx))
+		if child.OrdStatus != enum.OrdStatus_REJECTED {
+			open = open.Add(leavesQty)
+		}
+	}
+
+	if a.Active() {
+		open = a.Parent.QuantityDecimal.Sub(closed)
+	}
+
+	avgPx := decimal.Zero
+	if closed.IsPositive() {
+		avgPx = notional.Div(closed)
+	}
+
+	a.Parent.Closed = closed.String()
+	a.Parent.Open = open.String()
+	a.Parent.AvgPx = avgPx.StringFixed(4)
+}
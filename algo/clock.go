@@ -0,0 +1,16 @@
+package algo
+
+import "time"
+
+// Clock is the time source of the scheduler
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock is the wall clock
+type SystemClock struct{}
+
+// Now returns the current local time
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
@@ -0,0 +1,258 @@
+package algo
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/quickfixgo/enum"
+
+	"github.com/quickfixgo/traderui/oms"
+)
+
+// Router sends child orders to the counterparty
+type Router interface {
+	SendOrder(order *oms.Order) error
+	CancelOrder(order *oms.Order) error
+}
+
+// Scheduler works algos by releasing child orders as they come due
+type Scheduler struct {
+	sync.Mutex
+	// Validate checks child orders before they are sent, failing the algo if a child is refused,
+	// e.g. once its security is halted. Children are not checked if nil.
+	Validate func(child *oms.Order) error
+
+	algoID int
+	algos  map[int]*Algo
+
+	orderManager *oms.OrderManager
+	router       Router
+	clock        Clock
+}
+
+// NewScheduler returns a scheduler that saves parents to om and routes children through router
+func NewScheduler(om *oms.OrderManager, router Router, clock Clock) *Scheduler {
+	return &Scheduler{
+		algos:        make(map[int]*Algo),
+		orderManager: om,
+		router:       router,
+		clock:        clock,
+	}
+}
+
+// Submit starts working parent with params. The parent must already be initialized.
+func (s *Scheduler) Submit(parent *oms.Order, params Params) (*Algo, error) {
+	if err := params.Init(s.clock.Now()); err != nil {
+		return nil, err
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	s.orderManager.Lock()
+	_ = s.orderManager.Save(parent)
+	s.orderManager.Unlock()
+
+	s.algoID++
+	algo := &Algo{
+		ID:       s.algoID,
+		State:    Running,
+		Params:   params,
+		Parent:   parent,
+		Sent:     "0",
+		ChildIDs: []int{},
+	}
+	s.algos[algo.ID] = algo
+
+	s.work(algo)
+
+	return algo, nil
+}
+
+// Get returns the algo with id
+func (s *Scheduler) Get(id int) (*Algo, error) {
+	algo, ok := s.algos[id]
+	if !ok {
+		return nil, fmt.Errorf("could not find algo with id %v", id)
+	}
+
+	return algo, nil
+}
+
+// GetByParentID returns the algo working the parent order with id
+func (s *Scheduler) GetByParentID(id int) (*Algo, error) {
+	for _, algo := range s.algos {
+		if algo.Parent.ID == id {
+			return algo, nil
+		}
+	}
+
+	return nil, fmt.Errorf("could not find algo with parent id %v", id)
+}
+
+// GetAll returns all algos
+func (s *Scheduler) GetAll() []*Algo {
+	algos := make([]*Algo, 0, len(s.algos))
+	for _, v := range s.algos {
+		algos = append(algos, v)
+	}
+
+	return algos
+}
+
+// Pause stops releasing child orders, working children are left in the market
+func (s *Scheduler) Pause(id int) error {
+	s.Lock()
+	defer s.Unlock()
+
+	algo, err := s.Get(id)
+	if err != nil {
+		return err
+	}
+
+	if algo.State != Running {
+		return fmt.Errorf("cannot pause %v algo", algo.State)
+	}
+
+	algo.State = Paused
+	return nil
+}
+
+// Resume continues a paused algo, any slices that came due while paused are released at once
+func (s *Scheduler) Resume(id int) error {
+	s.Lock()
+	defer s.Unlock()
+
+	algo, err := s.Get(id)
+	if err != nil {
+		return err
+	}
+
+	if algo.State != Paused {
+		return fmt.Errorf("cannot resume %v algo", algo.State)
+	}
+
+	algo.State = Running
+	s.work(algo)
+	return nil
+}
+
+// Cancel stops the algo and cancels its working child orders
+func (s *Scheduler) Cancel(id int) error {
+	s.Lock()
+	defer s.Unlock()
+
+	algo, err := s.Get(id)
+	if err != nil {
+		return err
+	}
+
+	if !algo.Active() {
+		return fmt.Errorf("cannot cancel %v algo", algo.State)
+	}
+
+	algo.State = Cancelled
+
+	s.orderManager.RLock()
+	var working []*oms.Order
+	for _, child := range algo.children {
+		if child.Open != "0" && child.OrdStatus != enum.OrdStatus_REJECTED {
+			working = append(working, child)
+		}
+	}
+	s.orderManager.RUnlock()
+
+	for _, child := range working {
+		if err := s.router.CancelOrder(child); err != nil {
+			log.Printf("[ERROR] algo %v: cancel of child %v failed: %v\n", algo.ID, child.ID, err)
+		}
+	}
+
+	s.rollUp(algo)
+	return nil
+}
+
+// Tick releases all child orders due at the current clock time and rolls up fills
+func (s *Scheduler) Tick() {
+	s.Lock()
+	defer s.Unlock()
+
+	for _, algo := range s.algos {
+		s.work(algo)
+	}
+}
+
+// Run ticks the scheduler every interval until ctx is done
+func (s *Scheduler) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.Tick()
+		}
+	}
+}
+
+func (s *Scheduler) rollUp(algo *Algo) {
+	s.orderManager.Lock()
+	defer s.orderManager.Unlock()
+
+	parent := *algo.Parent
+	algo.rollUp()
+	if parent.Closed != algo.Parent.Closed || parent.Open != algo.Parent.Open || parent.AvgPx != algo.Parent.AvgPx {
+		s.orderManager.OrderUpdated(algo.Parent)
+	}
+}
+
+// send validates the child order and routes it
+func (s *Scheduler) send(child *oms.Order) error {
+	if s.Validate != nil {
+		if err := s.Validate(child); err != nil {
+			return err
+		}
+	}
+
+	return s.router.SendOrder(child)
+}
+
+func (s *Scheduler) work(algo *Algo) {
+	s.orderManager.RLock()
+	for _, child := range algo.children {
+		if child.OrdStatus == enum.OrdStatus_REJECTED && algo.Active() {
+			log.Printf("[ERROR] algo %v: child %v rejected\n", algo.ID, child.ID)
+			algo.State = Failed
+		}
+	}
+
+	algo.countSent()
+	var qty = algo.due(s.clock.Now())
+	s.orderManager.RUnlock()
+
+	if algo.State == Running && qty.IsPositive() {
+		child := algo.newChild(qty)
+		if err := s.send(child); err != nil {
+			log.Printf("[ERROR] algo %v: %v\n", algo.ID, err)
+			algo.State = Failed
+		}
+
+		if child.ID != 0 {
+			algo.children = append(algo.children, child)
+			algo.ChildIDs = append(algo.ChildIDs, child.ID)
+			algo.sent = algo.sent.Add(qty)
+			algo.Sent = algo.sent.String()
+		}
+	}
+
+	s.rollUp(algo)
+
+	if algo.State == Running && algo.sent.Equal(algo.Parent.QuantityDecimal) && algo.Parent.Open == "0" {
+		algo.State = Done
+	}
+}
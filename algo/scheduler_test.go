@@ -0,0 +1,379 @@
+package algo
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/quickfixgo/enum"
+	"github.com/shopspring/decimal"
+
+	"github.com/quickfixgo/traderui/oms"
+)
+
+var testStart = time.Date(2026, 10, 19, 14, 0, 0, 0, time.UTC)
+
+// fakeClock is a clock the test moves
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+type clOrdIDs struct {
+	n int
+}
+
+func (g *clOrdIDs) Next() string {
+	g.n++
+	return fmt.Sprintf("C%v", g.n)
+}
+
+// fakeRouter saves child orders and records cancels
+type fakeRouter struct {
+	om        *oms.OrderManager
+	sent      []*oms.Order
+	cancelled []*oms.Order
+}
+
+func (r *fakeRouter) SendOrder(order *oms.Order) error {
+	r.om.Lock()
+	defer r.om.Unlock()
+
+	_ = r.om.Save(order)
+	r.sent = append(r.sent, order)
+	return nil
+}
+
+func (r *fakeRouter) CancelOrder(order *oms.Order) error {
+	r.cancelled = append(r.cancelled, order)
+	return nil
+}
+
+// quantities returns the quantities of the child orders sent
+func (r *fakeRouter) quantities() []string {
+	qtys := make([]string, len(r.sent))
+	for i, child := range r.sent {
+		qtys[i] = child.Quantity
+	}
+
+	return qtys
+}
+
+func newTestScheduler(t *testing.T) (*Scheduler, *fakeRouter, *fakeClock) {
+	t.Helper()
+
+	om := oms.NewOrderManager(new(clOrdIDs))
+	router := &fakeRouter{om: om}
+	clock := &fakeClock{now: testStart}
+
+	return NewScheduler(om, router, clock), router, clock
+}
+
+func newParent(t *testing.T, quantity string) *oms.Order {
+	t.Helper()
+
+	parent := &oms.Order{Symbol: "IBM", Side: enum.Side_BUY, Quantity: quantity, OrdType: enum.OrdType_MARKET}
+	if err := parent.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	return parent
+}
+
+// fill reports qty of child filled at px, with the rest open
+func fill(s *Scheduler, child *oms.Order, qty, px string) {
+	s.orderManager.Lock()
+	defer s.orderManager.Unlock()
+
+	closed := decimal.RequireFromString(qty)
+	child.Closed = closed.String()
+	child.Open = child.QuantityDecimal.Sub(closed).String()
+	child.AvgPx = px
+	child.OrdStatus = enum.OrdStatus_PARTIALLY_FILLED
+	if child.Open == "0" {
+		child.OrdStatus = enum.OrdStatus_FILLED
+	}
+}
+
+func assertSent(t *testing.T, router *fakeRouter, want ...string) {
+	t.Helper()
+
+	if got := fmt.Sprint(router.quantities()); got != fmt.Sprint(want) {
+		t.Errorf("sent children of %v, want %v", got, want)
+	}
+}
+
+func TestTWAP(t *testing.T) {
+	s, router, clock := newTestScheduler(t)
+
+	a, err := s.Submit(newParent(t, "1000"), Params{Strategy: TWAP, EndTime: testStart.Add(10 * time.Minute), Slices: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertSent(t, router, "200")
+
+	clock.advance(time.Minute)
+	s.Tick()
+	assertSent(t, router, "200")
+
+	clock.advance(time.Minute)
+	s.Tick()
+	assertSent(t, router, "200", "200")
+
+	clock.advance(5 * time.Minute)
+	s.Tick()
+	assertSent(t, router, "200", "200", "400")
+
+	clock.advance(5 * time.Minute)
+	s.Tick()
+	assertSent(t, router, "200", "200", "400", "200")
+
+	if a.Sent != "1000" || a.State != Running {
+		t.Errorf("got sent %v state %v, want 1000 running until the children fill", a.Sent, a.State)
+	}
+	for _, child := range router.sent {
+		if child.ParentID != a.Parent.ID {
+			t.Errorf("child %v has parent %v, want %v", child.ID, child.ParentID, a.Parent.ID)
+		}
+		fill(s, child, child.Quantity, "10")
+	}
+
+	s.Tick()
+	if a.State != Done {
+		t.Errorf("got state %v, want done", a.State)
+	}
+}
+
+func TestTWAPDelayedStart(t *testing.T) {
+	s, router, clock := newTestScheduler(t)
+
+	params := Params{Strategy: TWAP, StartTime: testStart.Add(time.Hour), EndTime: testStart.Add(2 * time.Hour), Slices: 4}
+	if _, err := s.Submit(newParent(t, "100"), params); err != nil {
+		t.Fatal(err)
+	}
+	assertSent(t, router)
+
+	clock.advance(time.Hour)
+	s.Tick()
+	assertSent(t, router, "25")
+}
+
+func TestVWAPProfile(t *testing.T) {
+	s, router, clock := newTestScheduler(t)
+
+	params := Params{Strategy: VWAP, EndTime: testStart.Add(3 * time.Hour), Profile: []float64{1, 3, 0, 4}}
+	a, err := s.Submit(newParent(t, "800"), params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Params.Slices != 4 {
+		t.Fatalf("got %v slices, want one per profile weight", a.Params.Slices)
+	}
+	assertSent(t, router, "100")
+
+	clock.advance(45 * time.Minute)
+	s.Tick()
+	assertSent(t, router, "100", "300")
+
+	clock.advance(45 * time.Minute)
+	s.Tick()
+	assertSent(t, router, "100", "300")
+
+	clock.advance(45 * time.Minute)
+	s.Tick()
+	assertSent(t, router, "100", "300", "400")
+}
+
+func TestIcebergRefill(t *testing.T) {
+	s, router, _ := newTestScheduler(t)
+
+	a, err := s.Submit(newParent(t, "100"), Params{Strategy: Iceberg, DisplayQty: "40"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertSent(t, router, "40")
+
+	fill(s, router.sent[0], "30", "10")
+	s.Tick()
+	assertSent(t, router, "40")
+
+	fill(s, router.sent[0], "40", "10")
+	s.Tick()
+	assertSent(t, router, "40", "40")
+
+	fill(s, router.sent[1], "40", "10")
+	s.Tick()
+	assertSent(t, router, "40", "40", "20")
+
+	fill(s, router.sent[2], "20", "10")
+	s.Tick()
+	if a.State != Done || a.Parent.Closed != "100" {
+		t.Errorf("got state %v closed %v, want done with 100 closed", a.State, a.Parent.Closed)
+	}
+}
+
+func TestPauseResumeCancel(t *testing.T) {
+	s, router, clock := newTestScheduler(t)
+
+	a, err := s.Submit(newParent(t, "400"), Params{Strategy: TWAP, EndTime: testStart.Add(4 * time.Minute), Slices: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = s.Pause(a.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err = s.Pause(a.ID); err == nil {
+		t.Error("expected an error pausing a paused algo")
+	}
+
+	clock.advance(2 * time.Minute)
+	s.Tick()
+	assertSent(t, router, "100")
+
+	if err = s.Resume(a.ID); err != nil {
+		t.Fatal(err)
+	}
+	assertSent(t, router, "100", "200")
+
+	fill(s, router.sent[0], "100", "10")
+	if err = s.Cancel(a.ID); err != nil {
+		t.Fatal(err)
+	}
+	if len(router.cancelled) != 1 || router.cancelled[0] != router.sent[1] {
+		t.Errorf("cancelled %v, want only the working child", router.cancelled)
+	}
+	if a.State != Cancelled {
+		t.Errorf("got state %v, want cancelled", a.State)
+	}
+
+	clock.advance(time.Hour)
+	s.Tick()
+	assertSent(t, router, "100", "200")
+
+	if err = s.Resume(a.ID); err == nil {
+		t.Error("expected an error resuming a cancelled algo")
+	}
+	if err = s.Cancel(a.ID); err == nil {
+		t.Error("expected an error cancelling a cancelled algo")
+	}
+}
+
+func TestParentRollUp(t *testing.T) {
+	s, router, clock := newTestScheduler(t)
+
+	a, err := s.Submit(newParent(t, "1000"), Params{Strategy: TWAP, EndTime: testStart.Add(5 * time.Minute), Slices: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	clock.advance(time.Minute)
+	s.Tick()
+
+	fill(s, router.sent[0], "200", "10")
+	fill(s, router.sent[1], "100", "11")
+	s.Tick()
+
+	if a.Parent.Closed != "300" || a.Parent.Open != "700" || a.Parent.AvgPx != "10.3333" {
+		t.Errorf("got closed %v open %v avg px %v, want 300 700 10.3333", a.Parent.Closed, a.Parent.Open, a.Parent.AvgPx)
+	}
+
+	s.orderManager.Lock()
+	router.sent[1].OrdStatus = enum.OrdStatus_REJECTED
+	s.orderManager.Unlock()
+	s.Tick()
+	if a.State != Failed {
+		t.Errorf("got state %v, want failed after a rejected child", a.State)
+	}
+	if a.Parent.Closed != "300" || a.Parent.Open != "0" {
+		t.Errorf("got closed %v open %v, want 300 and nothing open once the algo stopped", a.Parent.Closed, a.Parent.Open)
+	}
+}
+
+func TestLotSize(t *testing.T) {
+	s, router, clock := newTestScheduler(t)
+
+	params := Params{Strategy: TWAP, EndTime: testStart.Add(3 * time.Minute), Slices: 3, LotSize: decimal.NewFromInt(100)}
+	if _, err := s.Submit(newParent(t, "1000"), params); err != nil {
+		t.Fatal(err)
+	}
+	assertSent(t, router, "300")
+
+	clock.advance(time.Minute)
+	s.Tick()
+	assertSent(t, router, "300", "300")
+
+	clock.advance(time.Minute)
+	s.Tick()
+	assertSent(t, router, "300", "300", "400")
+
+	iceberg := Params{Strategy: Iceberg, DisplayQty: "150", LotSize: decimal.NewFromInt(100)}
+	if _, err := s.Submit(newParent(t, "1000"), iceberg); err == nil {
+		t.Error("expected an error for a display quantity in odd lots")
+	}
+}
+
+func TestValidateChildren(t *testing.T) {
+	s, router, clock := newTestScheduler(t)
+
+	halted := false
+	s.Validate = func(child *oms.Order) error {
+		if halted {
+			return fmt.Errorf("Symbol %v is halted", child.Symbol)
+		}
+		return nil
+	}
+
+	a, err := s.Submit(newParent(t, "200"), Params{Strategy: TWAP, EndTime: testStart.Add(2 * time.Minute), Slices: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	halted = true
+	clock.advance(time.Minute)
+	s.Tick()
+	assertSent(t, router, "100")
+	if a.State != Failed {
+		t.Errorf("got state %v, want failed once a child is refused", a.State)
+	}
+}
+
+func TestChildCancelledByCounterparty(t *testing.T) {
+	s, router, clock := newTestScheduler(t)
+
+	a, err := s.Submit(newParent(t, "200"), Params{Strategy: TWAP, EndTime: testStart.Add(2 * time.Minute), Slices: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertSent(t, router, "100")
+
+	fill(s, router.sent[0], "40", "10")
+	s.orderManager.Lock()
+	router.sent[0].Open = "0"
+	router.sent[0].OrdStatus = enum.OrdStatus_CANCELED
+	s.orderManager.Unlock()
+
+	s.Tick()
+	assertSent(t, router, "100", "60")
+	if a.Sent != "100" || a.State != Running {
+		t.Errorf("got sent %v state %v, want the cancelled rest sliced again", a.Sent, a.State)
+	}
+
+	clock.advance(2 * time.Minute)
+	s.Tick()
+	assertSent(t, router, "100", "60", "100")
+
+	for _, child := range router.sent[1:] {
+		fill(s, child, child.Quantity, "10")
+	}
+	s.Tick()
+	if a.State != Done || a.Parent.Closed != "200" {
+		t.Errorf("got state %v closed %v, want done with 200 closed", a.State, a.Parent.Closed)
+	}
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/quickfixgo/traderui/algo"
+	"github.com/quickfixgo/traderui/auth"
+	"github.com/quickfixgo/traderui/oms"
+	"github.com/shopspring/decimal"
+)
+
+type algoRequest struct {
+	oms.Order
+	Algo algo.Params `json:"algo"`
+}
+
+func (c tradeClient) AlgosAsJSON(r *http.Request) (string, error) {
+	c.algos.Lock()
+	defer c.algos.Unlock()
+	c.RLock()
+	defer c.RUnlock()
+
+	algos := make([]*algo.Algo, 0)
+	for _, a := range c.algos.GetAll() {
+		if auth.CanView(r, a.Parent.Session, a.Parent.Account) {
+			algos = append(algos, a)
+		}
+	}
+
+	b, err := json.Marshal(algos)
+	return string(b), err
+}
+
+func (c tradeClient) fetchRequestedAlgoID(r *http.Request) int {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		panic(err)
+	}
+
+	return id
+}
+
+func (c tradeClient) writeAlgoJSON(w http.ResponseWriter, id int) {
+	c.algos.Lock()
+	defer c.algos.Unlock()
+	c.RLock()
+	defer c.RUnlock()
+
+	a, err := c.algos.Get(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	outgoingJSON, err := json.Marshal(a)
+	if err != nil {
+		log.Printf("[ERROR] err = %+v\n", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprint(w, string(outgoingJSON))
+}
+
+func (c tradeClient) getAlgos(w http.ResponseWriter, r *http.Request) {
+	outgoingJSON, err := c.AlgosAsJSON(r)
+	if err != nil {
+		log.Printf("[ERROR] err = %+v\n", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprint(w, outgoingJSON)
+}
+
+func (c tradeClient) getAlgo(w http.ResponseWriter, r *http.Request) {
+	id := c.fetchRequestedAlgoID(r)
+
+	c.algos.Lock()
+	a, err := c.algos.Get(id)
+	c.algos.Unlock()
+	if err != nil || !auth.CanView(r, a.Parent.Session, a.Parent.Account) {
+		http.Error(w, "Algo not found", http.StatusNotFound)
+		return
+	}
+
+	c.writeAlgoJSON(w, id)
+}
+
+func (c tradeClient) newAlgo(w http.ResponseWriter, r *http.Request) {
+	var req algoRequest
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&req)
+	if err != nil {
+		log.Printf("[ERROR] %v\n", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if !authorize(w, r, auth.ActionNew, req.Session, req.Account) {
+		return
+	}
+
+	req.User = auth.Username(r)
+	if err = c.validateOrder(&req.Order); err != nil {
+		log.Printf("[ERROR] %v\n", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	req.Algo.LotSize = c.lotSize(req.Symbol)
+	a, err := c.algos.Submit(&req.Order, req.Algo)
+	if err != nil {
+		log.Printf("[ERROR] %v\n", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	c.writeAlgoJSON(w, a.ID)
+}
+
+// lotSize returns the lot size of the symbol in the security master, zero if securities are not
+// validated
+func (c tradeClient) lotSize(symbol string) decimal.Decimal {
+	if !c.validateSecurities {
+		return decimal.Zero
+	}
+
+	c.securities.RLock()
+	defer c.securities.RUnlock()
+	if sec, ok := c.securities.Get(symbol); ok {
+		return sec.LotSize
+	}
+
+	return decimal.Zero
+}
+
+// authorizeAlgo checks the user of the request may perform action on the algo, writing an error if not
+func (c tradeClient) authorizeAlgo(w http.ResponseWriter, r *http.Request, id int, action auth.Action) bool {
+	c.algos.Lock()
+	defer c.algos.Unlock()
+
+	a, err := c.algos.Get(id)
+	if err != nil || !auth.CanView(r, a.Parent.Session, a.Parent.Account) {
+		http.Error(w, "Algo not found", http.StatusNotFound)
+		return false
+	}
+
+	if !authorize(w, r, action, a.Parent.Session, a.Parent.Account) {
+		return false
+	}
+
+	if !auth.CanModify(r, a.Parent.User) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return false
+	}
+
+	return true
+}
+
+func (c tradeClient) pauseAlgo(w http.ResponseWriter, r *http.Request) {
+	id := c.fetchRequestedAlgoID(r)
+	if !c.authorizeAlgo(w, r, id, auth.ActionAmend) {
+		return
+	}
+
+	if err := c.algos.Pause(id); err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+
+	c.writeAlgoJSON(w, id)
+}
+
+func (c tradeClient) resumeAlgo(w http.ResponseWriter, r *http.Request) {
+	id := c.fetchRequestedAlgoID(r)
+	if !c.authorizeAlgo(w, r, id, auth.ActionAmend) {
+		return
+	}
+
+	if err := c.algos.Resume(id); err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+
+	c.writeAlgoJSON(w, id)
+}
+
+func (c tradeClient) deleteAlgo(w http.ResponseWriter, r *http.Request) {
+	id := c.fetchRequestedAlgoID(r)
+	if !c.authorizeAlgo(w, r, id, auth.ActionCancel) {
+		return
+	}
+
+	if err := c.algos.Cancel(id); err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+
+	c.writeAlgoJSON(w, id)
+}
@@ -12,6 +12,34 @@ $(function() {
   });
 });
 
+function csrfToken() {
+  var match = document.cookie.match(/(?:^|; )traderui_csrf=([^;]*)/);
+  return match ? decodeURIComponent(match[1]) : "";
+}
+
+$.ajaxSetup({
+  beforeSend: function(xhr, settings) {
+    if (!/^(GET|HEAD|OPTIONS)$/.test(settings.type)) {
+      xhr.setRequestHeader("X-CSRF-Token", csrfToken());
+    }
+  }
+});
+
+$(document).ajaxError(function(event, xhr) {
+  if (xhr.status == 401) {
+    window.location = "/login";
+  }
+});
+
+$(function() {
+  $('#logout').click(function(event) {
+    event.preventDefault();
+    $.post("/logout").always(function() {
+      window.location = "/login";
+    });
+  });
+});
+
 setInterval(function() {
   App.orders.fetch({reset: true});
   App.executions.fetch({reset: true});
@@ -256,6 +284,14 @@ App.Views.OrderDetails = Backbone.View.extend({
       <p class="form-control-static"><%= avg_px %></p>
     </div>
   </div>
+  <% if(reject_reason){ %>
+  <div class="form-group">
+    <label class="col-sm-2 control-label">Reject Reason</label>
+    <div class="col-sm-10">
+      <p class="form-control-static text-danger"><%= reject_reason %></p>
+    </div>
+  </div>
+  <% } %>
   <div class="form-group">
     <label class="col-sm-2 control-label">Security Type</label>
     <div class="col-sm-10">
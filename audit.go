@@ -0,0 +1,76 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/quickfixgo/traderui/audit"
+	"github.com/quickfixgo/traderui/auth"
+)
+
+// parseAuditFilter reads the audit query parameters kind, user, session_id, clordid, since,
+// until (RFC 3339) and limit
+func parseAuditFilter(r *http.Request) (audit.Filter, error) {
+	query := r.URL.Query()
+	filter := audit.Filter{
+		Kind:    audit.Kind(query.Get("kind")),
+		User:    query.Get("user"),
+		Session: query.Get("session_id"),
+		ClOrdID: query.Get("clordid"),
+	}
+
+	var err error
+	if since := query.Get("since"); since != "" {
+		if filter.Since, err = time.Parse(time.RFC3339, since); err != nil {
+			return filter, fmt.Errorf("invalid since: %v", err)
+		}
+	}
+
+	if until := query.Get("until"); until != "" {
+		if filter.Until, err = time.Parse(time.RFC3339, until); err != nil {
+			return filter, fmt.Errorf("invalid until: %v", err)
+		}
+	}
+
+	if limit := query.Get("limit"); limit != "" {
+		if filter.Limit, err = strconv.Atoi(limit); err != nil || filter.Limit < 0 {
+			return filter, fmt.Errorf("invalid limit: %v", limit)
+		}
+	}
+
+	return filter, nil
+}
+
+func (c tradeClient) getAudit(w http.ResponseWriter, r *http.Request) {
+	if err := auth.AuthorizeAction(r, auth.ActionAdmin); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	filter, err := parseAuditFilter(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	records, err := c.auditLog.Query(filter)
+	if err != nil {
+		log.Printf("[ERROR] err = %+v\n", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	outgoingJSON, err := json.Marshal(records)
+	if err != nil {
+		log.Printf("[ERROR] err = %+v\n", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprint(w, string(outgoingJSON))
+}
@@ -0,0 +1,273 @@
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// Kind is the source of an audit record
+type Kind string
+
+// Record kinds
+const (
+	// HTTP is an action taken through the web api
+	HTTP Kind = "http"
+	// GRPC is an action taken through the gRPC api
+	GRPC Kind = "grpc"
+	// FIXIncoming is a raw message received from a counterparty
+	FIXIncoming Kind = "fix_in"
+	// FIXOutgoing is a raw message sent to a counterparty
+	FIXOutgoing Kind = "fix_out"
+	// FIXEvent is a session event logged by quickfix
+	FIXEvent Kind = "fix_event"
+	// FIXSendFailed is a message that could not be sent, e.g. when rate limited, with the error
+	// as payload
+	FIXSendFailed Kind = "fix_send_failed"
+)
+
+// minKeySize is the shortest key accepted for the record hashes
+const minKeySize = 16
+
+// maxRecordSize bounds the length of a line when reading the log back
+const maxRecordSize = 16 * 1024 * 1024
+
+// Record is one entry of the audit log. Each record carries the hash of the previous record,
+// so editing, removing or reordering records breaks the chain. Hashes are keyed, so the chain
+// cannot be recomputed without the key.
+type Record struct {
+	Seq        int64     `json:"seq"`
+	Time       time.Time `json:"time"`
+	Kind       Kind      `json:"kind"`
+	User       string    `json:"user,omitempty"`
+	RemoteAddr string    `json:"remote_addr,omitempty"`
+	Method     string    `json:"method,omitempty"`
+	Path       string    `json:"path,omitempty"`
+	Status     int       `json:"status,omitempty"`
+	Payload    string    `json:"payload,omitempty"`
+	Session    string    `json:"session_id,omitempty"`
+	ClOrdIDs   []string  `json:"cl_ord_ids,omitempty"`
+	Message    string    `json:"message,omitempty"`
+	PrevHash   string    `json:"prev_hash"`
+	Hash       string    `json:"hash"`
+}
+
+// computeHash returns the HMAC-SHA256 of the record contents under key, including PrevHash but
+// not Hash
+func (rec Record) computeHash(key []byte) (string, error) {
+	rec.Hash = ""
+	b, err := json.Marshal(rec)
+	if err != nil {
+		return "", err
+	}
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write(b)
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+// Head is the sequence number and hash of the last record of a log. It is kept next to the log
+// in HeadFile, and should be exported to where the log's writers cannot change it, as records
+// removed from the end of a log along with its head file are only detected against a copy.
+type Head struct {
+	Seq  int64  `json:"seq"`
+	Hash string `json:"hash"`
+}
+
+// String returns the head as seq:hash
+func (h Head) String() string {
+	return fmt.Sprintf("%v:%v", h.Seq, h.Hash)
+}
+
+// HeadFile returns the name of the head file of the log in fileName
+func HeadFile(fileName string) string {
+	return fileName + ".head"
+}
+
+// ReadHead reads the head file of the log in fileName
+func ReadHead(fileName string) (Head, error) {
+	var head Head
+	b, err := os.ReadFile(HeadFile(fileName))
+	if err != nil {
+		return head, err
+	}
+
+	err = json.Unmarshal(b, &head)
+	return head, err
+}
+
+// LoadKey reads the key of the record hashes from fileName
+func LoadKey(fileName string) ([]byte, error) {
+	b, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	key := bytes.TrimSpace(b)
+	if len(key) < minKeySize {
+		return nil, fmt.Errorf("audit key %v is shorter than %v bytes", fileName, minKeySize)
+	}
+
+	return key, nil
+}
+
+// Log is an append-only, hash-chained audit log stored as json lines
+type Log struct {
+	mu       sync.Mutex
+	fileName string
+	key      []byte
+	file     *os.File
+	head     *os.File
+	seq      int64
+	lastHash string
+}
+
+// Open opens or creates the audit log in fileName, hashing records with key. An existing log is
+// verified, and must end at its head, before records are appended to it.
+func Open(fileName string, key []byte) (*Log, error) {
+	if len(key) < minKeySize {
+		return nil, fmt.Errorf("audit key is shorter than %v bytes", minKeySize)
+	}
+	l := &Log{fileName: fileName, key: key}
+
+	if f, err := os.Open(fileName); err == nil {
+		last, err := Verify(f, key)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("audit log %v failed verification: %v", fileName, err)
+		}
+
+		head, err := ReadHead(fileName)
+		if err != nil && !(os.IsNotExist(err) && last.Seq == 0) {
+			return nil, fmt.Errorf("audit log %v: reading head: %v", fileName, err)
+		}
+		if head != last {
+			return nil, fmt.Errorf("audit log %v ends at %v, its head is %v", fileName, last, head)
+		}
+
+		l.seq, l.lastHash = last.Seq, last.Hash
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	} else if head, err := ReadHead(fileName); err == nil && head.Seq > 0 {
+		return nil, fmt.Errorf("audit log %v is missing, its head is %v", fileName, head)
+	}
+
+	f, err := os.OpenFile(fileName, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, err
+	}
+	l.file = f
+
+	if l.head, err = os.OpenFile(HeadFile(fileName), os.O_CREATE|os.O_WRONLY, 0600); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return l, nil
+}
+
+// Head returns the head of the log
+func (l *Log) Head() Head {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return Head{Seq: l.seq, Hash: l.lastHash}
+}
+
+// writeHead replaces the contents of the head file with head
+func (l *Log) writeHead(head Head) error {
+	b, err := json.Marshal(head)
+	if err != nil {
+		return err
+	}
+
+	if err = l.head.Truncate(0); err != nil {
+		return err
+	}
+
+	_, err = l.head.WriteAt(append(b, '\n'), 0)
+	return err
+}
+
+// Append chains the record to the log and writes it
+func (l *Log) Append(rec *Record) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	rec.Seq = l.seq + 1
+	if rec.Time.IsZero() {
+		rec.Time = time.Now().UTC()
+	}
+	rec.PrevHash = l.lastHash
+
+	var err error
+	if rec.Hash, err = rec.computeHash(l.key); err != nil {
+		return err
+	}
+
+	b, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+
+	if _, err = l.file.Write(append(b, '\n')); err != nil {
+		return err
+	}
+
+	l.seq, l.lastHash = rec.Seq, rec.Hash
+	return l.writeHead(Head{Seq: rec.Seq, Hash: rec.Hash})
+}
+
+// Close closes the underlying files
+func (l *Log) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return errors.Join(l.file.Close(), l.head.Close())
+}
+
+// Verify checks the hash chain of a log under key, returning the head of the valid records
+func Verify(r io.Reader, key []byte) (Head, error) {
+	var last Head
+	prevHash := ""
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxRecordSize)
+	for line := int64(1); scanner.Scan(); line++ {
+		var rec Record
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			return last, fmt.Errorf("record %v: %v", line, err)
+		}
+
+		if rec.Seq != line {
+			return last, fmt.Errorf("record %v: out of sequence, has seq %v", line, rec.Seq)
+		}
+
+		if rec.PrevHash != prevHash {
+			return last, fmt.Errorf("record %v: previous hash does not match", line)
+		}
+
+		hash, err := rec.computeHash(key)
+		if err != nil {
+			return last, fmt.Errorf("record %v: %v", line, err)
+		}
+
+		if hash != rec.Hash {
+			return last, fmt.Errorf("record %v: hash does not match contents", line)
+		}
+
+		prevHash = rec.Hash
+		last = Head{Seq: rec.Seq, Hash: rec.Hash}
+	}
+
+	return last, scanner.Err()
+}
@@ -0,0 +1,202 @@
+package audit
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/quickfixgo/traderui/auth"
+)
+
+var testKey = []byte("0123456789abcdef0123456789abcdef")
+
+// writeLog writes a log of n records to a temporary file, returning its name
+func writeLog(t *testing.T, n int) string {
+	t.Helper()
+
+	fileName := filepath.Join(t.TempDir(), "audit.log")
+	l, err := Open(fileName, testKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	for i := 0; i < n; i++ {
+		if err = l.Append(&Record{Kind: HTTP, User: "alice", Path: "/orders"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return fileName
+}
+
+// readLines returns the records of the log in fileName
+func readLines(t *testing.T, fileName string) []string {
+	t.Helper()
+
+	b, err := os.ReadFile(fileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return strings.SplitAfter(strings.TrimSuffix(string(b), "\n"), "\n")
+}
+
+func writeLines(t *testing.T, fileName string, lines []string) {
+	t.Helper()
+
+	if err := os.WriteFile(fileName, []byte(strings.Join(lines, "")), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	fileName := writeLog(t, 3)
+	b, err := os.ReadFile(fileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	head, err := Verify(bytes.NewReader(b), testKey)
+	if err != nil || head.Seq != 3 {
+		t.Fatalf("got %v %v, want 3 valid records", head, err)
+	}
+	if stored, err := ReadHead(fileName); err != nil || stored != head {
+		t.Errorf("got head file %v %v, want %v", stored, err, head)
+	}
+
+	if _, err = Verify(bytes.NewReader(b), []byte("another key of 32 bytes........")); err == nil {
+		t.Error("expected an error verifying under another key")
+	}
+}
+
+func TestVerifyTampering(t *testing.T) {
+	tests := []struct {
+		name   string
+		tamper func(lines []string) []string
+	}{
+		{"edit", func(lines []string) []string {
+			lines[1] = strings.Replace(lines[1], `"user":"alice"`, `"user":"bob"`, 1)
+			return lines
+		}},
+		{"reorder", func(lines []string) []string {
+			lines[1], lines[2] = lines[2], lines[1]
+			return lines
+		}},
+		{"remove", func(lines []string) []string {
+			return append(lines[:1], lines[2:]...)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fileName := writeLog(t, 3)
+			writeLines(t, fileName, tt.tamper(readLines(t, fileName)))
+
+			f, err := os.Open(fileName)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+
+			if head, err := Verify(f, testKey); err == nil || head.Seq != 1 {
+				t.Errorf("got %v %v, want an error after the first record", head, err)
+			}
+
+			if _, err = Open(fileName, testKey); err == nil {
+				t.Error("opened a tampered log")
+			}
+		})
+	}
+}
+
+func TestOpenTruncated(t *testing.T) {
+	fileName := writeLog(t, 3)
+	writeLines(t, fileName, readLines(t, fileName)[:2])
+
+	if _, err := Open(fileName, testKey); err == nil {
+		t.Error("opened a log cut short of its head")
+	}
+
+	if err := os.Remove(fileName); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Open(fileName, testKey); err == nil {
+		t.Error("opened a removed log with a head")
+	}
+}
+
+func TestOpenAppends(t *testing.T) {
+	fileName := writeLog(t, 2)
+
+	l, err := Open(fileName, testKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = l.Append(&Record{Kind: FIXEvent, Message: "logon"}); err != nil {
+		t.Fatal(err)
+	}
+	head := l.Head()
+	l.Close()
+
+	if head.Seq != 3 {
+		t.Errorf("got head %v, want the chain continued at 3", head)
+	}
+	if _, err = Open(fileName, testKey); err != nil {
+		t.Errorf("reopening: %v", err)
+	}
+
+	if _, err = Open(fileName, []byte("short")); err == nil {
+		t.Error("expected an error for a short key")
+	}
+}
+
+func TestMiddlewareRecordsRefusedRequests(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "audit.log")
+	l, err := Open(fileName, testKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	hash, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	authenticator := auth.NewAuthenticator(map[string]*auth.User{"alice": {Username: "alice", PasswordHash: string(hash)}})
+	session, ok := authenticator.Login("alice", "secret")
+	if !ok {
+		t.Fatal("login failed")
+	}
+
+	ok200 := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	h := l.Middleware()(authenticator.Middleware(RecordUser(ok200)))
+
+	r := httptest.NewRequest("POST", "/orders", strings.NewReader(`{"symbol":"IBM"}`))
+	r.AddCookie(&http.Cookie{Name: auth.SessionCookie, Value: session.Token})
+	h.ServeHTTP(httptest.NewRecorder(), r)
+
+	r = httptest.NewRequest("POST", "/orders", strings.NewReader(`{"symbol":"IBM"}`))
+	r.Header.Set("Authorization", "Bearer "+session.Token)
+	h.ServeHTTP(httptest.NewRecorder(), r)
+
+	records, err := l.Query(Filter{Kind: HTTP})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("got %v records, want both requests", len(records))
+	}
+	if records[0].Status != http.StatusUnauthorized || records[0].User != "" || records[0].Payload == "" {
+		t.Errorf("got %+v, want the request without csrf token recorded as unauthorized", records[0])
+	}
+	if records[1].Status != http.StatusOK || records[1].User != "alice" {
+		t.Errorf("got %+v, want the request recorded with its user", records[1])
+	}
+}
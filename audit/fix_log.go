@@ -0,0 +1,110 @@
+package audit
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+// clOrdIDTags are the ClOrdID(11) and OrigClOrdID(41) field prefixes in a raw message. Linking
+// on OrigClOrdID ties cancels and their execution reports to the original order.
+var clOrdIDTags = [][]byte{[]byte("\x0111="), []byte("\x0141=")}
+
+// clOrdIDs returns the ClOrdIDs in a raw message, including those of list orders
+func clOrdIDs(msg []byte) []string {
+	var ids []string
+	for _, tag := range clOrdIDTags {
+		for rest := msg; ; {
+			i := bytes.Index(rest, tag)
+			if i < 0 {
+				break
+			}
+
+			rest = rest[i+len(tag):]
+			end := bytes.IndexByte(rest, '\x01')
+			if end < 0 {
+				end = len(rest)
+			}
+			ids = append(ids, string(rest[:end]))
+		}
+	}
+
+	return ids
+}
+
+type fixLog struct {
+	audit   *Log
+	session string
+	next    quickfix.Log
+}
+
+func (l fixLog) append(rec *Record) {
+	rec.Session = l.session
+	if err := l.audit.Append(rec); err != nil {
+		log.Printf("[ERROR] audit: %v\n", err)
+	}
+}
+
+func (l fixLog) OnIncoming(msg []byte) {
+	l.append(&Record{Kind: FIXIncoming, Message: string(msg), ClOrdIDs: clOrdIDs(msg)})
+	l.next.OnIncoming(msg)
+}
+
+func (l fixLog) OnOutgoing(msg []byte) {
+	l.append(&Record{Kind: FIXOutgoing, Message: string(msg), ClOrdIDs: clOrdIDs(msg)})
+	l.next.OnOutgoing(msg)
+}
+
+func (l fixLog) OnEvent(s string) {
+	l.append(&Record{Kind: FIXEvent, Message: s})
+	l.next.OnEvent(s)
+}
+
+func (l fixLog) OnEventf(format string, a ...interface{}) {
+	l.OnEvent(fmt.Sprintf(format, a...))
+}
+
+type logFactory struct {
+	audit *Log
+	next  quickfix.LogFactory
+}
+
+func (f logFactory) Create() (quickfix.Log, error) {
+	next, err := f.next.Create()
+	if err != nil {
+		return nil, err
+	}
+
+	return fixLog{f.audit, "", next}, nil
+}
+
+func (f logFactory) CreateSessionLog(sessionID quickfix.SessionID) (quickfix.Log, error) {
+	next, err := f.next.CreateSessionLog(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return fixLog{f.audit, sessionID.String(), next}, nil
+}
+
+// NewLogFactory returns a LogFactory that records raw messages and events to the audit log
+// before passing them on to next
+func (l *Log) NewLogFactory(next quickfix.LogFactory) quickfix.LogFactory {
+	return logFactory{l, next}
+}
+
+// RecordSend records a message that could not be sent, matching sender.Hook. Messages sent are
+// recorded by the log factory once the session writes them.
+func (l *Log) RecordSend(msg *quickfix.Message, sessionID quickfix.SessionID, err error) {
+	if err == nil {
+		return
+	}
+
+	raw := []byte(msg.String())
+	rec := &Record{Kind: FIXSendFailed, Session: sessionID.String(), Message: string(raw), ClOrdIDs: clOrdIDs(raw), Payload: err.Error()}
+	if err := l.Append(rec); err != nil {
+		log.Printf("[ERROR] audit: %v\n", err)
+	}
+}
@@ -0,0 +1,127 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/quickfixgo/traderui/auth"
+)
+
+// maxPayloadSize bounds the request body recorded for an action
+const maxPayloadSize = 1024 * 1024
+
+type contextKey struct{}
+
+// entry collects what handlers report about an action while it is served
+type entry struct {
+	sync.Mutex
+	user     string
+	clOrdIDs []string
+}
+
+// AddClOrdIDs links the ClOrdIDs sent by the handler of r to the audit record of the request
+func AddClOrdIDs(r *http.Request, clOrdIDs ...string) {
+	e, ok := r.Context().Value(contextKey{}).(*entry)
+	if !ok {
+		return
+	}
+
+	e.Lock()
+	defer e.Unlock()
+	e.clOrdIDs = append(e.clOrdIDs, clOrdIDs...)
+}
+
+// RecordUser notes the authenticated user on the audit record of the request. Middleware runs
+// before authentication, so that refused requests are recorded too, and does not see the user
+// otherwise; RecordUser goes after the authentication middleware.
+func RecordUser(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if e, ok := r.Context().Value(contextKey{}).(*entry); ok {
+			e.Lock()
+			e.user = auth.Username(r)
+			e.Unlock()
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Track returns ctx collecting the ClOrdIDs added while serving an action recorded outside of
+// Middleware, and a function returning them once the action is done
+func Track(ctx context.Context) (context.Context, func() []string) {
+	e := new(entry)
+	return context.WithValue(ctx, contextKey{}, e), func() []string {
+		e.Lock()
+		defer e.Unlock()
+		return e.clOrdIDs
+	}
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusRecorder) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Middleware records every state changing request to the log along with the status it was
+// served with, including requests refused by middleware registered after it. Payloads of paths prefixed by
+// one of redact, such as login credentials, are not recorded.
+func (l *Log) Middleware(redact ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			var payload []byte
+			if r.Body != nil {
+				payload, _ = io.ReadAll(io.LimitReader(r.Body, maxPayloadSize))
+				r.Body = io.NopCloser(io.MultiReader(bytes.NewReader(payload), r.Body))
+			}
+
+			for _, prefix := range redact {
+				if strings.HasPrefix(r.URL.Path, prefix) {
+					payload = nil
+				}
+			}
+
+			e := new(entry)
+			rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(rec, r.WithContext(context.WithValue(r.Context(), contextKey{}, e)))
+
+			e.Lock()
+			defer e.Unlock()
+
+			user := e.user
+			if user == "" {
+				user = auth.Username(r)
+			}
+
+			err := l.Append(&Record{
+				Kind:       HTTP,
+				User:       user,
+				RemoteAddr: r.RemoteAddr,
+				Method:     r.Method,
+				Path:       r.URL.RequestURI(),
+				Status:     rec.status,
+				Payload:    string(payload),
+				ClOrdIDs:   e.clOrdIDs,
+			})
+
+			if err != nil {
+				log.Printf("[ERROR] audit: %v\n", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"time"
+)
+
+// Filter selects records of the log. Zero values match any record.
+type Filter struct {
+	Kind    Kind
+	User    string
+	Session string
+	ClOrdID string
+	Since   time.Time
+	Until   time.Time
+	// Limit returns at most the last Limit matching records
+	Limit int
+}
+
+func (f Filter) matches(rec *Record) bool {
+	switch {
+	case f.Kind != "" && rec.Kind != f.Kind:
+		return false
+	case f.User != "" && rec.User != f.User:
+		return false
+	case f.Session != "" && rec.Session != f.Session:
+		return false
+	case !f.Since.IsZero() && rec.Time.Before(f.Since):
+		return false
+	case !f.Until.IsZero() && !rec.Time.Before(f.Until):
+		return false
+	}
+
+	if f.ClOrdID == "" {
+		return true
+	}
+
+	for _, clOrdID := range rec.ClOrdIDs {
+		if clOrdID == f.ClOrdID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Query returns the records matching filter in log order
+func (l *Log) Query(filter Filter) ([]*Record, error) {
+	f, err := os.Open(l.fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	records := make([]*Record, 0)
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), maxRecordSize)
+	for scanner.Scan() {
+		rec := new(Record)
+		if err := json.Unmarshal(scanner.Bytes(), rec); err != nil {
+			// a record still being written
+			break
+		}
+
+		if !filter.matches(rec) {
+			continue
+		}
+
+		records = append(records, rec)
+		if filter.Limit > 0 && len(records) > filter.Limit {
+			records = records[1:]
+		}
+	}
+
+	return records, scanner.Err()
+}
@@ -0,0 +1,220 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// SessionCookie holds the session token of a logged in browser
+	SessionCookie = "traderui_session"
+	// CSRFCookie holds the csrf token, readable by scripts so it can be echoed in CSRFHeader
+	CSRFCookie = "traderui_csrf"
+	// CSRFHeader must carry the csrf token on state changing requests authenticated by cookie
+	CSRFHeader = "X-CSRF-Token"
+
+	sessionTTL = 12 * time.Hour
+)
+
+// dummyUser is checked for unknown usernames, so logins take as long whether the user exists or not
+var dummyUser = &User{PasswordHash: "$2a$10$n3El/hyjAa6D84JoiXl8X.EoKjuiZjZM0LhUshbDb2E4qoym08zM."}
+
+type contextKey struct{}
+
+// Session is a logged in user
+type Session struct {
+	Token     string    `json:"token"`
+	CSRFToken string    `json:"csrf_token"`
+	Username  string    `json:"username"`
+	Role      Role      `json:"role"`
+	Expires   time.Time `json:"expires"`
+
+	user *User
+}
+
+// Authenticator logs users in and authenticates requests by session cookie or bearer token
+type Authenticator struct {
+	sync.Mutex
+	users    map[string]*User
+	sessions map[string]*Session
+
+	// Public are path prefixes served without authentication
+	Public []string
+}
+
+// NewAuthenticator returns an authenticator for users
+func NewAuthenticator(users map[string]*User) *Authenticator {
+	return &Authenticator{
+		users:    users,
+		sessions: make(map[string]*Session),
+		Public:   []string{"/login", "/assets/"},
+	}
+}
+
+// Login checks the credentials and starts a new session. The password is checked without
+// holding the lock, so logins do not stall authenticated requests.
+func (a *Authenticator) Login(username, password string) (*Session, bool) {
+	a.Lock()
+	user, ok := a.users[username]
+	a.Unlock()
+
+	if !ok {
+		dummyUser.checkPassword(password)
+		return nil, false
+	}
+	if !user.checkPassword(password) {
+		return nil, false
+	}
+
+	s := &Session{
+		Token:     newToken(),
+		CSRFToken: newToken(),
+		Username:  user.Username,
+		Role:      user.Role,
+		Expires:   time.Now().Add(sessionTTL),
+		user:      user,
+	}
+
+	a.Lock()
+	defer a.Unlock()
+
+	a.sweep(time.Now())
+	a.sessions[s.Token] = s
+
+	return s, true
+}
+
+// sweep deletes the sessions expired at now
+func (a *Authenticator) sweep(now time.Time) {
+	for token, s := range a.sessions {
+		if now.After(s.Expires) {
+			delete(a.sessions, token)
+		}
+	}
+}
+
+// Logout ends the session with token
+func (a *Authenticator) Logout(token string) {
+	a.Lock()
+	defer a.Unlock()
+
+	delete(a.sessions, token)
+}
+
+func (a *Authenticator) session(token string) (*Session, bool) {
+	a.Lock()
+	defer a.Unlock()
+
+	s, ok := a.sessions[token]
+	if !ok {
+		return nil, false
+	}
+
+	if time.Now().After(s.Expires) {
+		delete(a.sessions, token)
+		return nil, false
+	}
+
+	return s, true
+}
+
+// Authenticate returns the session of the request. Bearer tokens are checked first. Requests
+// authenticated by cookie must echo the csrf token unless they are safe methods.
+func (a *Authenticator) Authenticate(r *http.Request) (*Session, bool) {
+	if header := r.Header.Get("Authorization"); strings.HasPrefix(header, "Bearer ") {
+		return a.session(strings.TrimPrefix(header, "Bearer "))
+	}
+
+	cookie, err := r.Cookie(SessionCookie)
+	if err != nil {
+		return nil, false
+	}
+
+	s, ok := a.session(cookie.Value)
+	if !ok {
+		return nil, false
+	}
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return s, true
+	}
+
+	if subtle.ConstantTimeCompare([]byte(r.Header.Get(CSRFHeader)), []byte(s.CSRFToken)) != 1 {
+		return nil, false
+	}
+
+	return s, true
+}
+
+// Middleware rejects unauthenticated requests and adds the user to the request context
+func (a *Authenticator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, prefix := range a.Public {
+			if strings.HasPrefix(r.URL.Path, prefix) {
+				next.ServeHTTP(w, r)
+				return
+			}
+		}
+
+		s, ok := a.Authenticate(r)
+		if !ok {
+			if r.Method == http.MethodGet && strings.Contains(r.Header.Get("Accept"), "text/html") {
+				http.Redirect(w, r, "/login", http.StatusSeeOther)
+				return
+			}
+
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), contextKey{}, s.user)))
+	})
+}
+
+// AuthenticateToken returns ctx carrying the user of the session with token, for callers outside
+// of http such as the gRPC api
+func (a *Authenticator) AuthenticateToken(ctx context.Context, token string) (context.Context, bool) {
+	s, ok := a.session(token)
+	if !ok {
+		return ctx, false
+	}
+
+	return context.WithValue(ctx, contextKey{}, s.user), true
+}
+
+// UserFromContext returns the authenticated user, nil if authentication is disabled
+func UserFromContext(ctx context.Context) *User {
+	user, _ := ctx.Value(contextKey{}).(*User)
+	return user
+}
+
+// Username returns the name of the authenticated user, empty if authentication is disabled
+func Username(r *http.Request) string {
+	if user := UserFromContext(r.Context()); user != nil {
+		return user.Username
+	}
+
+	return ""
+}
+
+// CanModify is true if the user of the request may cancel or amend orders submitted by owner
+func CanModify(r *http.Request, owner string) bool {
+	user := UserFromContext(r.Context())
+	return user == nil || user.CanModify(owner)
+}
+
+func newToken() string {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,154 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// newTestAuthenticator returns an authenticator of the trader alice and the supervisor sam, both
+// with the password secret
+func newTestAuthenticator(t *testing.T) *Authenticator {
+	t.Helper()
+
+	hash, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	users := make(map[string]*User)
+	for name, role := range map[string]Role{"alice": Trader, "sam": Supervisor} {
+		users[name] = &User{Username: name, PasswordHash: string(hash), Role: role, policy: defaultPolicies[role]}
+	}
+
+	return NewAuthenticator(users)
+}
+
+func login(t *testing.T, a *Authenticator, username string) *Session {
+	t.Helper()
+
+	s, ok := a.Login(username, "secret")
+	if !ok {
+		t.Fatalf("login of %v failed", username)
+	}
+
+	return s
+}
+
+// serve passes r through the middleware of a, returning the status
+func serve(a *Authenticator, r *http.Request) int {
+	w := httptest.NewRecorder()
+	a.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})).ServeHTTP(w, r)
+	return w.Code
+}
+
+func TestLogin(t *testing.T) {
+	a := newTestAuthenticator(t)
+
+	s := login(t, a, "alice")
+	if s.Username != "alice" || s.Role != Trader || s.Token == "" || s.CSRFToken == "" {
+		t.Errorf("got session %+v", s)
+	}
+
+	if _, ok := a.Login("alice", "wrong"); ok {
+		t.Error("logged in with a wrong password")
+	}
+	if _, ok := a.Login("mallory", "secret"); ok {
+		t.Error("logged in as an unknown user")
+	}
+}
+
+func TestCSRF(t *testing.T) {
+	a := newTestAuthenticator(t)
+	s := login(t, a, "alice")
+
+	withCookie := func(method string) *http.Request {
+		r := httptest.NewRequest(method, "/orders", nil)
+		r.AddCookie(&http.Cookie{Name: SessionCookie, Value: s.Token})
+		return r
+	}
+
+	if code := serve(a, withCookie("GET")); code != http.StatusOK {
+		t.Errorf("cookie GET: got %v, want %v", code, http.StatusOK)
+	}
+
+	if code := serve(a, withCookie("POST")); code != http.StatusUnauthorized {
+		t.Errorf("cookie POST without csrf token: got %v, want %v", code, http.StatusUnauthorized)
+	}
+
+	r := withCookie("POST")
+	r.Header.Set(CSRFHeader, "forged")
+	if code := serve(a, r); code != http.StatusUnauthorized {
+		t.Errorf("cookie POST with a wrong csrf token: got %v, want %v", code, http.StatusUnauthorized)
+	}
+
+	r = withCookie("POST")
+	r.Header.Set(CSRFHeader, s.CSRFToken)
+	if code := serve(a, r); code != http.StatusOK {
+		t.Errorf("cookie POST with the csrf token: got %v, want %v", code, http.StatusOK)
+	}
+
+	r = httptest.NewRequest("POST", "/orders", nil)
+	r.Header.Set("Authorization", "Bearer "+s.Token)
+	if code := serve(a, r); code != http.StatusOK {
+		t.Errorf("bearer POST: got %v, want %v without a csrf token", code, http.StatusOK)
+	}
+}
+
+func TestPublic(t *testing.T) {
+	a := newTestAuthenticator(t)
+
+	for _, path := range []string{"/login", "/assets/app.js"} {
+		if code := serve(a, httptest.NewRequest("GET", path, nil)); code != http.StatusOK {
+			t.Errorf("%v: got %v, want %v", path, code, http.StatusOK)
+		}
+	}
+
+	if code := serve(a, httptest.NewRequest("GET", "/orders", nil)); code != http.StatusUnauthorized {
+		t.Errorf("got %v, want %v", code, http.StatusUnauthorized)
+	}
+
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set("Accept", "text/html")
+	if code := serve(a, r); code != http.StatusSeeOther {
+		t.Errorf("browser: got %v, want a redirect to the login page", code)
+	}
+}
+
+func TestExpiredSession(t *testing.T) {
+	a := newTestAuthenticator(t)
+	s := login(t, a, "alice")
+	other := login(t, a, "alice")
+
+	a.Lock()
+	s.Expires = time.Now().Add(-time.Second)
+	other.Expires = s.Expires
+	a.Unlock()
+
+	r := httptest.NewRequest("GET", "/orders", nil)
+	r.Header.Set("Authorization", "Bearer "+s.Token)
+	if code := serve(a, r); code != http.StatusUnauthorized {
+		t.Errorf("got %v, want %v", code, http.StatusUnauthorized)
+	}
+
+	login(t, a, "sam")
+	a.Lock()
+	defer a.Unlock()
+	if _, ok := a.sessions[other.Token]; ok || len(a.sessions) != 1 {
+		t.Errorf("got %v sessions, want expired sessions swept on login", len(a.sessions))
+	}
+}
+
+func TestCanModify(t *testing.T) {
+	a := newTestAuthenticator(t)
+
+	if alice := a.users["alice"]; !alice.CanModify("alice") || alice.CanModify("bob") {
+		t.Error("want traders to modify only their own orders")
+	}
+	if !a.users["sam"].CanModify("bob") {
+		t.Error("want supervisors to modify orders of other users")
+	}
+}
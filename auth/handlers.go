@@ -0,0 +1,84 @@
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"mime"
+	"net/http"
+)
+
+type credentials struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// HandleLogin logs in with a form or json body. Browsers get session and csrf cookies, the json
+// response carries the token for use as a bearer token.
+func (a *Authenticator) HandleLogin(w http.ResponseWriter, r *http.Request) {
+	var creds credentials
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "application/json" {
+		if err := json.NewDecoder(r.Body).Decode(&creds); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	} else {
+		creds.Username = r.PostFormValue("username")
+		creds.Password = r.PostFormValue("password")
+	}
+
+	s, ok := a.Login(creds.Username, creds.Password)
+	if !ok {
+		log.Printf("[ERROR] failed login for %v from %v\n", creds.Username, r.RemoteAddr)
+		http.Error(w, "Invalid username or password", http.StatusUnauthorized)
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     SessionCookie,
+		Value:    s.Token,
+		Path:     "/",
+		Expires:  s.Expires,
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteStrictMode,
+	})
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     CSRFCookie,
+		Value:    s.CSRFToken,
+		Path:     "/",
+		Expires:  s.Expires,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteStrictMode,
+	})
+
+	if mediaType != "application/json" {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+
+	outgoingJSON, err := json.Marshal(s)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprint(w, string(outgoingJSON))
+}
+
+// HandleLogout ends the session of the request
+func (a *Authenticator) HandleLogout(w http.ResponseWriter, r *http.Request) {
+	if s, ok := a.Authenticate(r); ok {
+		a.Logout(s.Token)
+	}
+
+	for _, name := range []string{SessionCookie, CSRFCookie} {
+		http.SetCookie(w, &http.Cookie{Name: name, Value: "", Path: "/", MaxAge: -1})
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,129 @@
+package auth
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// Action is something a user may be permitted to do
+type Action string
+
+// Actions
+const (
+	ActionNew    Action = "new"
+	ActionCancel Action = "cancel"
+	ActionAmend  Action = "amend"
+	ActionSecDef Action = "secdef"
+	// ActionAdmin allows acting on orders of other users and administrative endpoints
+	ActionAdmin Action = "admin"
+)
+
+// Wildcard matches any session or account in a policy
+const Wildcard = "*"
+
+// Policy restricts what the users of a role may see and do. Sessions and accounts scope both
+// viewing and trading, a role without actions is view-only.
+type Policy struct {
+	Sessions []string `json:"sessions"`
+	Accounts []string `json:"accounts"`
+	Actions  []Action `json:"actions"`
+}
+
+// defaultPolicies are used when the users file defines no roles
+var defaultPolicies = map[Role]*Policy{
+	Trader: {
+		Sessions: []string{Wildcard},
+		Accounts: []string{Wildcard},
+		Actions:  []Action{ActionNew, ActionCancel, ActionAmend, ActionSecDef},
+	},
+	Supervisor: {
+		Sessions: []string{Wildcard},
+		Accounts: []string{Wildcard},
+		Actions:  []Action{ActionNew, ActionCancel, ActionAmend, ActionSecDef, ActionAdmin},
+	},
+	Viewer: {
+		Sessions: []string{Wildcard},
+		Accounts: []string{Wildcard},
+	},
+}
+
+func matches(allowed []string, value string) bool {
+	for _, a := range allowed {
+		if a == Wildcard || a == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AllowsSession is true if the session is in scope
+func (p *Policy) AllowsSession(session string) bool {
+	return matches(p.Sessions, session)
+}
+
+// AllowsAccount is true if the account is in scope
+func (p *Policy) AllowsAccount(account string) bool {
+	return matches(p.Accounts, account)
+}
+
+// Allows is true if the action is permitted
+func (p *Policy) Allows(action Action) bool {
+	for _, a := range p.Actions {
+		if a == action {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CanView is true if the user of the request may see activity on session and account
+func CanView(r *http.Request, session, account string) bool {
+	user := UserFromContext(r.Context())
+	return user == nil || (user.policy.AllowsSession(session) && user.policy.AllowsAccount(account))
+}
+
+// CanUseSession is true if the user of the request may see session
+func CanUseSession(r *http.Request, session string) bool {
+	user := UserFromContext(r.Context())
+	return user == nil || user.policy.AllowsSession(session)
+}
+
+// AuthorizeAction checks the user of the request may perform action
+func AuthorizeAction(r *http.Request, action Action) error {
+	user := UserFromContext(r.Context())
+	if user != nil && !user.policy.Allows(action) {
+		return fmt.Errorf("%v may not %v", user.Username, action)
+	}
+
+	return nil
+}
+
+// AuthorizeSession checks the user of the request may perform action on session
+func AuthorizeSession(r *http.Request, action Action, session string) error {
+	if err := AuthorizeAction(r, action); err != nil {
+		return err
+	}
+
+	user := UserFromContext(r.Context())
+	if user != nil && !user.policy.AllowsSession(session) {
+		return fmt.Errorf("%v may not use session %v", user.Username, session)
+	}
+
+	return nil
+}
+
+// Authorize checks the user of the request may perform action on session and account
+func Authorize(r *http.Request, action Action, session, account string) error {
+	if err := AuthorizeSession(r, action, session); err != nil {
+		return err
+	}
+
+	user := UserFromContext(r.Context())
+	if user != nil && !user.policy.AllowsAccount(account) {
+		return fmt.Errorf("%v may not use account %v", user.Username, account)
+	}
+
+	return nil
+}
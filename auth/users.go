@@ -0,0 +1,103 @@
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Role is the role of a user
+type Role string
+
+// Default roles, used when the users file defines no roles
+const (
+	Trader     Role = "trader"
+	Supervisor Role = "supervisor"
+	Viewer     Role = "viewer"
+)
+
+// User is a local user allowed to log in
+type User struct {
+	Username     string `json:"username"`
+	PasswordHash string `json:"password_hash"`
+	Role         Role   `json:"role"`
+
+	policy *Policy
+}
+
+// IsAdmin is true if the user may act on orders of other users
+func (u *User) IsAdmin() bool {
+	return u.policy.Allows(ActionAdmin)
+}
+
+// CanModify is true if the user may cancel or amend orders submitted by owner
+func (u *User) CanModify(owner string) bool {
+	return u.IsAdmin() || u.Username == owner
+}
+
+// checkPassword compares password with the user's bcrypt hash
+func (u *User) checkPassword(password string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte(password)) == nil
+}
+
+type usersFile struct {
+	Roles map[Role]*Policy `json:"roles"`
+	Users []*User          `json:"users"`
+}
+
+// LoadUsers reads users and their role policies from a json file of the form
+//
+//	{
+//	  "roles": {"trader": {"sessions": ["*"], "accounts": ["*"], "actions": ["new", "cancel"]}},
+//	  "users": [{"username": "alice", "password_hash": "$2a$10$...", "role": "trader"}]
+//	}
+//
+// The trader, supervisor and viewer roles are used if no roles are defined.
+func LoadUsers(fileName string) (map[string]*User, error) {
+	b, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	var f usersFile
+	if err = json.Unmarshal(b, &f); err != nil {
+		return nil, fmt.Errorf("error reading %v: %v", fileName, err)
+	}
+
+	if f.Roles == nil {
+		f.Roles = defaultPolicies
+	}
+
+	for role, policy := range f.Roles {
+		for _, action := range policy.Actions {
+			switch action {
+			case ActionNew, ActionCancel, ActionAmend, ActionSecDef, ActionAdmin:
+			default:
+				return nil, fmt.Errorf("error reading %v: role %v has unknown action %v", fileName, role, action)
+			}
+		}
+	}
+
+	users := make(map[string]*User, len(f.Users))
+	for _, u := range f.Users {
+		if u.Username == "" || u.PasswordHash == "" {
+			return nil, fmt.Errorf("error reading %v: users need a username and password_hash", fileName)
+		}
+
+		if u.Role == "" {
+			u.Role = Trader
+		}
+
+		policy, ok := f.Roles[u.Role]
+		if !ok {
+			return nil, fmt.Errorf("error reading %v: unknown role %v", fileName, u.Role)
+		}
+		u.policy = policy
+
+		users[u.Username] = u
+	}
+
+	return users, nil
+}
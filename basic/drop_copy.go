@@ -0,0 +1,79 @@
+package basic
+
+import (
+	"github.com/quickfixgo/field"
+	"github.com/quickfixgo/tag"
+	"github.com/quickfixgo/traderui/oms"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+// externalOrder returns the order for an execution report received on a drop copy session for an
+// unknown ClOrdID. Replaces of known external orders are linked through OrigClOrdID, anything else
+// is saved as a new synthetic order built from the report.
+func (a *FIXApplication) externalOrder(msg *quickfix.Message, sessionID quickfix.SessionID, clOrdID string) (*oms.Order, error) {
+	if origClOrdID, err := msg.Body.GetString(tag.OrigClOrdID); err == nil {
+		if order, err := a.GetByClOrdID(origClOrdID); err == nil {
+			a.AssignClOrdID(order, clOrdID)
+			return order, nil
+		}
+	}
+
+	order := &oms.Order{
+		ClOrdID:   clOrdID,
+		SessionID: sessionID,
+		Session:   sessionID.String(),
+	}
+
+	order.OrderID, _ = msg.Body.GetString(tag.OrderID)
+	order.Symbol, _ = msg.Body.GetString(tag.Symbol)
+	order.Account, _ = msg.Body.GetString(tag.Account)
+	order.SecurityDesc, _ = msg.Body.GetString(tag.SecurityDesc)
+
+	var side field.SideField
+	if err := msg.Body.Get(&side); err == nil {
+		order.Side = side.Value()
+	}
+
+	var ordType field.OrdTypeField
+	if err := msg.Body.Get(&ordType); err == nil {
+		order.OrdType = ordType.Value()
+	}
+
+	var securityType field.SecurityTypeField
+	if err := msg.Body.Get(&securityType); err == nil {
+		order.SecurityType = securityType.Value()
+	}
+
+	var orderQty field.OrderQtyField
+	if err := msg.Body.Get(&orderQty); err == nil {
+		order.QuantityDecimal = orderQty.Value()
+	} else {
+		var cumQty field.CumQtyField
+		var leavesQty field.LeavesQtyField
+		if err := msg.Body.Get(&cumQty); err == nil {
+			if err := msg.Body.Get(&leavesQty); err == nil {
+				order.QuantityDecimal = cumQty.Value().Add(leavesQty.Value())
+			}
+		}
+	}
+	order.Quantity = order.QuantityDecimal.String()
+
+	var price field.PriceField
+	if err := msg.Body.Get(&price); err == nil {
+		order.PriceDecimal = price.Value()
+		order.Price = price.String()
+	}
+
+	var stopPx field.StopPxField
+	if err := msg.Body.Get(&stopPx); err == nil {
+		order.StopPriceDecimal = stopPx.Value()
+		order.StopPrice = stopPx.String()
+	}
+
+	if err := a.SaveExternal(order); err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
@@ -6,7 +6,10 @@ import (
 	"github.com/quickfixgo/enum"
 	"github.com/quickfixgo/field"
 	"github.com/quickfixgo/tag"
+	"github.com/quickfixgo/traderui/metrics"
 	"github.com/quickfixgo/traderui/oms"
+	"github.com/quickfixgo/traderui/rfq"
+	"github.com/quickfixgo/traderui/secmaster"
 
 	"github.com/quickfixgo/quickfix"
 )
@@ -15,42 +18,93 @@ import (
 type FIXApplication struct {
 	SessionIDs map[string]quickfix.SessionID
 	*oms.OrderManager
+	RFQs *rfq.Manager
+
+	// Securities is refreshed from security definitions and lists
+	Securities *secmaster.Store
+
+	// DropCopySessions receive copies of executions for orders placed elsewhere
+	DropCopySessions map[quickfix.SessionID]bool
+
+	Metrics *metrics.Metrics
+
+	status sessionStatuses
 }
 
-// OnLogon is ignored
-func (a *FIXApplication) OnLogon(sessionID quickfix.SessionID) {}
+// OnLogon records the session as logged on
+func (a *FIXApplication) OnLogon(sessionID quickfix.SessionID) {
+	a.status.loggedOn(sessionID, true)
+	a.Metrics.LoggedOn(sessionID.String(), true)
+}
 
-// OnLogout is ignored
-func (a *FIXApplication) OnLogout(sessionID quickfix.SessionID) {}
+// OnLogout records the session as logged out
+func (a *FIXApplication) OnLogout(sessionID quickfix.SessionID) {
+	a.status.loggedOn(sessionID, false)
+	a.Metrics.LoggedOn(sessionID.String(), false)
+}
 
-// ToAdmin is ignored
-func (a *FIXApplication) ToAdmin(msg *quickfix.Message, sessionID quickfix.SessionID) {}
+// ToAdmin counts outgoing admin messages
+func (a *FIXApplication) ToAdmin(msg *quickfix.Message, sessionID quickfix.SessionID) {
+	a.countSent(msg, sessionID)
+}
 
 // OnCreate initialized SessionIDs
 func (a *FIXApplication) OnCreate(sessionID quickfix.SessionID) {
 	a.SessionIDs[sessionID.String()] = sessionID
+	a.status.created(sessionID)
+	a.Metrics.LoggedOn(sessionID.String(), false)
 }
 
-// FromAdmin is ignored
+// FromAdmin counts incoming admin messages and tracks heartbeats
 func (a *FIXApplication) FromAdmin(msg *quickfix.Message, sessionID quickfix.SessionID) (reject quickfix.MessageRejectError) {
+	msgType, _ := msg.MsgType()
+	a.status.received(sessionID, msgType)
+	a.Metrics.MessageReceived(sessionID.String(), msgType)
 	return
 }
 
-// ToApp is ignored
+// ToApp counts outgoing messages and times orders until their first execution report
 func (a *FIXApplication) ToApp(msg *quickfix.Message, sessionID quickfix.SessionID) (err error) {
+	msgType := a.countSent(msg, sessionID)
+	switch enum.MsgType(msgType) {
+	case enum.MsgType_ORDER_SINGLE, enum.MsgType_NEW_ORDER_MULTILEG:
+		if clOrdID, err := msg.Body.GetString(tag.ClOrdID); err == nil {
+			a.Metrics.OrderSent(clOrdID)
+		}
+	}
+
 	return
 }
 
-// FromApp listens for just execution reports
+func (a *FIXApplication) countSent(msg *quickfix.Message, sessionID quickfix.SessionID) string {
+	msgType, _ := msg.MsgType()
+	a.Metrics.MessageSent(sessionID.String(), msgType)
+	return msgType
+}
+
+// FromApp listens for execution reports, cancel rejects, quote responses and security reference data
 func (a *FIXApplication) FromApp(msg *quickfix.Message, sessionID quickfix.SessionID) quickfix.MessageRejectError {
 	msgType, err := msg.MsgType()
 	if err != nil {
 		return err
 	}
+	a.Metrics.MessageReceived(sessionID.String(), msgType)
 
 	switch enum.MsgType(msgType) {
 	case enum.MsgType_EXECUTION_REPORT:
 		return a.onExecutionReport(msg, sessionID)
+	case enum.MsgType_ORDER_CANCEL_REJECT:
+		return a.onOrderCancelReject(msg, sessionID)
+	case enum.MsgType_QUOTE:
+		return a.onQuote(msg, sessionID)
+	case enum.MsgType_QUOTE_REQUEST_REJECT:
+		return a.onQuoteRequestReject(msg, sessionID)
+	case enum.MsgType_QUOTE_CANCEL:
+		return a.onQuoteCancel(msg, sessionID)
+	case enum.MsgType_SECURITY_DEFINITION:
+		return a.onSecurityDefinition(msg, sessionID)
+	case enum.MsgType_SECURITY_LIST:
+		return a.onSecurityList(msg, sessionID)
 	}
 
 	return quickfix.UnsupportedMessageType()
@@ -65,11 +119,24 @@ func (a *FIXApplication) onExecutionReport(msg *quickfix.Message, sessionID quic
 		return err
 	}
 
+	execType, _ := msg.Body.GetString(tag.ExecType)
+	a.Metrics.ExecutionReport(sessionID.String(), clOrdID.String(), execType)
+
 	order, err := a.GetByClOrdID(clOrdID.String())
+	switch {
+	case err != nil && a.DropCopySessions[sessionID]:
+		order, err = a.externalOrder(msg, sessionID, clOrdID.String())
+
+	// the order session reports on orders placed here, their copies would record each fill twice
+	case err == nil && a.DropCopySessions[sessionID] && !order.External:
+		return nil
+	}
+
 	if err != nil {
 		log.Printf("[ERROR] err= %v", err)
 		return nil
 	}
+	defer a.OrderUpdated(order)
 
 	var cumQty field.CumQtyField
 	if err := msg.Body.Get(&cumQty); err != nil {
@@ -86,9 +153,41 @@ func (a *FIXApplication) onExecutionReport(msg *quickfix.Message, sessionID quic
 		return err
 	}
 
-	order.Closed = cumQty.String()
-	order.Open = leavesQty.String()
-	order.AvgPx = avgPx.String()
+	var ordStatus field.OrdStatusField
+	if err := msg.Body.Get(&ordStatus); err != nil {
+		return err
+	}
+
+	switch enum.ExecType(execType) {
+	case enum.ExecType_REPLACED, enum.ExecType_CANCELED:
+		order.RejectReason = ""
+	}
+
+	if execType == string(enum.ExecType_REPLACED) {
+		if err := onReplaced(msg, order, clOrdID.String()); err != nil {
+			return err
+		}
+	}
+
+	if ordStatus.Value() == enum.OrdStatus_REJECTED && order.OrdStatus != enum.OrdStatus_REJECTED {
+		a.Metrics.OrderRejected(sessionID.String(), metrics.RejectedCounterparty)
+	}
+
+	var reportingType field.MultiLegReportingTypeField
+	if msg.Body.Has(tag.MultiLegReportingType) {
+		if err := msg.Body.Get(&reportingType); err != nil {
+			return err
+		}
+	}
+
+	// reports on an individual leg carry the leg's quantities, not the order's
+	legReport := reportingType.Value() == enum.MultiLegReportingType_INDIVIDUAL_LEG_OF_A_MULTI_LEG_SECURITY
+	if !legReport {
+		order.Closed = cumQty.String()
+		order.Open = leavesQty.String()
+		order.AvgPx = avgPx.String()
+		order.OrdStatus = ordStatus.Value()
+	}
 
 	if msg.Body.Has(tag.LastShares) {
 		var lastShares field.LastSharesField
@@ -101,10 +200,21 @@ func (a *FIXApplication) onExecutionReport(msg *quickfix.Message, sessionID quic
 			return err
 		}
 
+		if legReport {
+			return a.saveLegReportExecution(msg, order, lastShares, price)
+		}
+
+		if len(order.Legs) > 0 && msg.Body.Has(tag.NoLegs) {
+			if saved, err := a.saveLegExecutions(msg, order, lastShares); saved || err != nil {
+				return err
+			}
+		}
+
 		exec := new(oms.Execution)
 		exec.Symbol = order.Symbol
 		exec.Side = order.Side
 		exec.Session = order.Session
+		exec.Account = order.Account
 
 		exec.Quantity = lastShares.String()
 		exec.Price = price.String()
@@ -113,3 +223,93 @@ func (a *FIXApplication) onExecutionReport(msg *quickfix.Message, sessionID quic
 
 	return nil
 }
+
+// cxlRejReasons describe the CxlRejReason of cancel rejects without a Text
+var cxlRejReasons = map[enum.CxlRejReason]string{
+	enum.CxlRejReason_TOO_LATE_TO_CANCEL: "too late to cancel",
+	enum.CxlRejReason_UNKNOWN_ORDER:      "unknown order",
+	enum.CxlRejReason_BROKER:             "broker option",
+	enum.CxlRejReason_ORDER_ALREADY_IN_PENDING_CANCEL_OR_PENDING_REPLACE_STATUS: "order already pending cancel or replace",
+	enum.CxlRejReason_DUPLICATE_CLORDID:                                         "duplicate ClOrdID",
+}
+
+// onOrderCancelReject drops the ClOrdID of the rejected cancel or replace and shows the reason on
+// the order, which continues under its current ClOrdID
+func (a *FIXApplication) onOrderCancelReject(msg *quickfix.Message, sessionID quickfix.SessionID) quickfix.MessageRejectError {
+	a.Lock()
+	defer a.Unlock()
+
+	var clOrdID field.ClOrdIDField
+	if err := msg.Body.Get(&clOrdID); err != nil {
+		return err
+	}
+
+	order, err := a.GetByClOrdID(clOrdID.String())
+	if err != nil {
+		origClOrdID, _ := msg.Body.GetString(tag.OrigClOrdID)
+		if order, err = a.GetByClOrdID(origClOrdID); err != nil {
+			log.Printf("[ERROR] err= %v", err)
+			return nil
+		}
+	}
+
+	if a.DropCopySessions[sessionID] && !order.External {
+		return nil
+	}
+	defer a.OrderUpdated(order)
+
+	a.UnassignClOrdID(clOrdID.String())
+
+	request := "Cancel"
+	if responseTo, _ := msg.Body.GetString(tag.CxlRejResponseTo); enum.CxlRejResponseTo(responseTo) == enum.CxlRejResponseTo_ORDER_CANCEL_REPLACE_REQUEST {
+		request = "Replace"
+	}
+
+	reason, _ := msg.Body.GetString(tag.Text)
+	if reason == "" {
+		code, _ := msg.Body.GetString(tag.CxlRejReason)
+		reason = cxlRejReasons[enum.CxlRejReason(code)]
+	}
+	order.RejectReason = request + " rejected"
+	if reason != "" {
+		order.RejectReason += ": " + reason
+	}
+
+	var ordStatus field.OrdStatusField
+	if msg.Body.Get(&ordStatus) == nil {
+		order.OrdStatus = ordStatus.Value()
+	}
+
+	return nil
+}
+
+// onReplaced applies an accepted cancel/replace to the order, which continues under the new ClOrdID
+func onReplaced(msg *quickfix.Message, order *oms.Order, clOrdID string) quickfix.MessageRejectError {
+	order.ClOrdID = clOrdID
+
+	if msg.Body.Has(tag.OrderQty) {
+		var qty field.OrderQtyField
+		if err := msg.Body.Get(&qty); err != nil {
+			return err
+		}
+		order.QuantityDecimal, order.Quantity = qty.Value(), qty.String()
+	}
+
+	if msg.Body.Has(tag.Price) {
+		var price field.PriceField
+		if err := msg.Body.Get(&price); err != nil {
+			return err
+		}
+		order.PriceDecimal, order.Price = price.Value(), price.String()
+	}
+
+	if msg.Body.Has(tag.StopPx) {
+		var stopPx field.StopPxField
+		if err := msg.Body.Get(&stopPx); err != nil {
+			return err
+		}
+		order.StopPriceDecimal, order.StopPrice = stopPx.Value(), stopPx.String()
+	}
+
+	return nil
+}
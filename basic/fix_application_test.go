@@ -0,0 +1,294 @@
+package basic
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/quickfixgo/enum"
+	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/tag"
+	"github.com/quickfixgo/traderui/oms"
+)
+
+var testSessionID = quickfix.SessionID{BeginString: quickfix.BeginStringFIX42, SenderCompID: "TW", TargetCompID: "ISLD"}
+
+// report is an execution report of a scenario
+type report struct {
+	clOrdID, origClOrdID string
+	execType             enum.ExecType
+	ordStatus            enum.OrdStatus
+	cumQty, leavesQty    string
+	avgPx                string
+	lastShares, lastPx   string
+	orderQty, price      string
+}
+
+// message returns the report as received from the counterparty
+func (r report) message(t *testing.T) *quickfix.Message {
+	t.Helper()
+
+	m := quickfix.NewMessage()
+	m.Header.SetString(tag.BeginString, testSessionID.BeginString)
+	m.Header.SetString(tag.MsgType, string(enum.MsgType_EXECUTION_REPORT))
+	m.Header.SetString(tag.SenderCompID, testSessionID.TargetCompID)
+	m.Header.SetString(tag.TargetCompID, testSessionID.SenderCompID)
+
+	body := map[quickfix.Tag]string{
+		tag.ClOrdID: r.clOrdID, tag.OrigClOrdID: r.origClOrdID, tag.ExecType: string(r.execType),
+		tag.OrdStatus: string(r.ordStatus), tag.CumQty: r.cumQty, tag.LeavesQty: r.leavesQty, tag.AvgPx: r.avgPx,
+		tag.LastShares: r.lastShares, tag.LastPx: r.lastPx, tag.OrderQty: r.orderQty, tag.Price: r.price,
+	}
+	for tg, value := range body {
+		if value != "" {
+			m.Body.SetString(tg, value)
+		}
+	}
+
+	msg := quickfix.NewMessage()
+	if err := quickfix.ParseMessage(msg, bytes.NewBufferString(m.String())); err != nil {
+		t.Fatal(err)
+	}
+
+	return msg
+}
+
+func newTestApplication(t *testing.T) (*FIXApplication, *oms.Order) {
+	t.Helper()
+
+	app := &FIXApplication{
+		SessionIDs:   map[string]quickfix.SessionID{testSessionID.String(): testSessionID},
+		OrderManager: oms.NewOrderManager(new(ClOrdIDGenerator)),
+	}
+
+	order := testOrder(t, testSessionID.BeginString, enum.OrdType_LIMIT)
+	order.Session = testSessionID.String()
+	if err := app.Save(&order); err != nil {
+		t.Fatal(err)
+	}
+
+	return app, &order
+}
+
+func TestExecutionReportScenarios(t *testing.T) {
+	tests := []struct {
+		name           string
+		reports        func(order *oms.Order, cxlClOrdID string) []report
+		want           oms.Order
+		wantExecutions []oms.Execution
+	}{
+		{
+			name: "partial fill",
+			reports: func(o *oms.Order, _ string) []report {
+				return []report{
+					{clOrdID: o.ClOrdID, execType: enum.ExecType_NEW, ordStatus: enum.OrdStatus_NEW, cumQty: "0", leavesQty: "250", avgPx: "0"},
+					{clOrdID: o.ClOrdID, execType: enum.ExecType_PARTIAL_FILL, ordStatus: enum.OrdStatus_PARTIALLY_FILLED, cumQty: "100", leavesQty: "150", avgPx: "101.25", lastShares: "100", lastPx: "101.25"},
+				}
+			},
+			want:           oms.Order{OrdStatus: enum.OrdStatus_PARTIALLY_FILLED, Closed: "100", Open: "150", AvgPx: "101.25"},
+			wantExecutions: []oms.Execution{{Quantity: "100", Price: "101.25"}},
+		},
+		{
+			name: "fill",
+			reports: func(o *oms.Order, _ string) []report {
+				return []report{
+					{clOrdID: o.ClOrdID, execType: enum.ExecType_NEW, ordStatus: enum.OrdStatus_NEW, cumQty: "0", leavesQty: "250", avgPx: "0"},
+					{clOrdID: o.ClOrdID, execType: enum.ExecType_PARTIAL_FILL, ordStatus: enum.OrdStatus_PARTIALLY_FILLED, cumQty: "100", leavesQty: "150", avgPx: "101", lastShares: "100", lastPx: "101"},
+					{clOrdID: o.ClOrdID, execType: enum.ExecType_FILL, ordStatus: enum.OrdStatus_FILLED, cumQty: "250", leavesQty: "0", avgPx: "101.15", lastShares: "150", lastPx: "101.25"},
+				}
+			},
+			want:           oms.Order{OrdStatus: enum.OrdStatus_FILLED, Closed: "250", Open: "0", AvgPx: "101.15"},
+			wantExecutions: []oms.Execution{{Quantity: "100", Price: "101"}, {Quantity: "150", Price: "101.25"}},
+		},
+		{
+			name: "cancel",
+			reports: func(o *oms.Order, cxlClOrdID string) []report {
+				return []report{
+					{clOrdID: o.ClOrdID, execType: enum.ExecType_NEW, ordStatus: enum.OrdStatus_NEW, cumQty: "0", leavesQty: "250", avgPx: "0"},
+					{clOrdID: cxlClOrdID, origClOrdID: o.ClOrdID, execType: enum.ExecType_CANCELED, ordStatus: enum.OrdStatus_CANCELED, cumQty: "0", leavesQty: "0", avgPx: "0"},
+				}
+			},
+			want: oms.Order{OrdStatus: enum.OrdStatus_CANCELED, Closed: "0", Open: "0", AvgPx: "0"},
+		},
+		{
+			name: "reject",
+			reports: func(o *oms.Order, _ string) []report {
+				return []report{
+					{clOrdID: o.ClOrdID, execType: enum.ExecType_REJECTED, ordStatus: enum.OrdStatus_REJECTED, cumQty: "0", leavesQty: "0", avgPx: "0"},
+				}
+			},
+			want: oms.Order{OrdStatus: enum.OrdStatus_REJECTED, Closed: "0", Open: "0", AvgPx: "0"},
+		},
+		{
+			name: "replace",
+			reports: func(o *oms.Order, cxlClOrdID string) []report {
+				return []report{
+					{clOrdID: o.ClOrdID, execType: enum.ExecType_NEW, ordStatus: enum.OrdStatus_NEW, cumQty: "0", leavesQty: "250", avgPx: "0"},
+					{clOrdID: cxlClOrdID, origClOrdID: o.ClOrdID, execType: enum.ExecType_REPLACED, ordStatus: enum.OrdStatus_REPLACED, cumQty: "0", leavesQty: "300", avgPx: "0", orderQty: "300", price: "101.5"},
+				}
+			},
+			want: oms.Order{OrdStatus: enum.OrdStatus_REPLACED, Closed: "0", Open: "300", AvgPx: "0", Quantity: "300", Price: "101.5"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app, order := newTestApplication(t)
+			origClOrdID := order.ClOrdID
+			cxlClOrdID := app.AssignNextClOrdID(order)
+
+			for _, r := range tt.reports(order, cxlClOrdID) {
+				if err := app.FromApp(r.message(t), testSessionID); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if order.OrdStatus != tt.want.OrdStatus || order.Closed != tt.want.Closed || order.Open != tt.want.Open || order.AvgPx != tt.want.AvgPx {
+				t.Errorf("got status %v closed %v open %v avg px %v, want %v %v %v %v", order.OrdStatus, order.Closed, order.Open, order.AvgPx,
+					tt.want.OrdStatus, tt.want.Closed, tt.want.Open, tt.want.AvgPx)
+			}
+
+			if tt.want.Quantity != "" {
+				if order.Quantity != tt.want.Quantity || order.Price != tt.want.Price || order.ClOrdID != cxlClOrdID {
+					t.Errorf("got quantity %v price %v clordid %v, want %v %v %v", order.Quantity, order.Price, order.ClOrdID,
+						tt.want.Quantity, tt.want.Price, cxlClOrdID)
+				}
+			} else if order.ClOrdID != origClOrdID {
+				t.Errorf("got clordid %v, want %v", order.ClOrdID, origClOrdID)
+			}
+
+			executions := app.GetAllExecutions()
+			if len(executions) != len(tt.wantExecutions) {
+				t.Fatalf("got %v executions, want %v", len(executions), len(tt.wantExecutions))
+			}
+			for i, exec := range executions {
+				want := tt.wantExecutions[i]
+				if exec.Quantity != want.Quantity || exec.Price != want.Price || exec.Symbol != order.Symbol || exec.Side != order.Side || exec.Session != order.Session {
+					t.Errorf("execution %v: got %+v, want %+v of %v %v on %v", i, *exec, want, order.Side, order.Symbol, order.Session)
+				}
+			}
+		})
+	}
+}
+
+func TestExecutionReportUnknownOrder(t *testing.T) {
+	app, _ := newTestApplication(t)
+
+	r := report{clOrdID: "unknown", execType: enum.ExecType_FILL, ordStatus: enum.OrdStatus_FILLED, cumQty: "10", leavesQty: "0", avgPx: "1", lastShares: "10", lastPx: "1"}
+	if err := app.FromApp(r.message(t), testSessionID); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := len(app.GetAll()); n != 1 {
+		t.Errorf("got %v orders, want 1", n)
+	}
+	if n := len(app.GetAllExecutions()); n != 0 {
+		t.Errorf("got %v executions, want none", n)
+	}
+}
+
+func TestExecutionReportDropCopy(t *testing.T) {
+	app, _ := newTestApplication(t)
+	app.DropCopySessions = map[quickfix.SessionID]bool{testSessionID: true}
+
+	r := report{clOrdID: "EXT1", execType: enum.ExecType_FILL, ordStatus: enum.OrdStatus_FILLED, cumQty: "10", leavesQty: "0", avgPx: "1", lastShares: "10", lastPx: "1", orderQty: "10"}
+	if err := app.FromApp(r.message(t), testSessionID); err != nil {
+		t.Fatal(err)
+	}
+
+	order, err := app.GetByClOrdID("EXT1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !order.External || order.OrdStatus != enum.OrdStatus_FILLED || order.Quantity != "10" {
+		t.Errorf("got external %v status %v quantity %v, want an external filled order of 10", order.External, order.OrdStatus, order.Quantity)
+	}
+}
+
+func TestExecutionReportDropCopyOfOwnOrder(t *testing.T) {
+	app, order := newTestApplication(t)
+	dropCopy := testSessionID
+	dropCopy.TargetCompID = "DROPCOPY"
+	app.DropCopySessions = map[quickfix.SessionID]bool{dropCopy: true}
+
+	r := report{clOrdID: order.ClOrdID, execType: enum.ExecType_PARTIAL_FILL, ordStatus: enum.OrdStatus_PARTIALLY_FILLED, cumQty: "100", leavesQty: "150", avgPx: "101.25", lastShares: "100", lastPx: "101.25"}
+	for _, sessionID := range []quickfix.SessionID{testSessionID, dropCopy} {
+		if err := app.FromApp(r.message(t), sessionID); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if n := len(app.GetAllExecutions()); n != 1 {
+		t.Errorf("got %v executions, want the fill recorded once", n)
+	}
+	if order.Closed != "100" || order.Open != "150" || len(app.GetAll()) != 1 {
+		t.Errorf("got closed %v open %v and %v orders, want the order filled once", order.Closed, order.Open, len(app.GetAll()))
+	}
+}
+
+// cancelReject returns an OrderCancelReject of the request with clOrdID as received from the counterparty
+func cancelReject(t *testing.T, clOrdID, origClOrdID string, responseTo enum.CxlRejResponseTo, body map[quickfix.Tag]string) *quickfix.Message {
+	t.Helper()
+
+	m := quickfix.NewMessage()
+	m.Header.SetString(tag.BeginString, testSessionID.BeginString)
+	m.Header.SetString(tag.MsgType, string(enum.MsgType_ORDER_CANCEL_REJECT))
+	m.Header.SetString(tag.SenderCompID, testSessionID.TargetCompID)
+	m.Header.SetString(tag.TargetCompID, testSessionID.SenderCompID)
+	m.Body.SetString(tag.ClOrdID, clOrdID)
+	m.Body.SetString(tag.OrigClOrdID, origClOrdID)
+	m.Body.SetString(tag.OrderID, "O1")
+	m.Body.SetString(tag.OrdStatus, string(enum.OrdStatus_NEW))
+	m.Body.SetString(tag.CxlRejResponseTo, string(responseTo))
+	for tg, value := range body {
+		m.Body.SetString(tg, value)
+	}
+
+	msg := quickfix.NewMessage()
+	if err := quickfix.ParseMessage(msg, bytes.NewBufferString(m.String())); err != nil {
+		t.Fatal(err)
+	}
+
+	return msg
+}
+
+func TestOrderCancelReject(t *testing.T) {
+	tests := []struct {
+		name       string
+		responseTo enum.CxlRejResponseTo
+		body       map[quickfix.Tag]string
+		want       string
+	}{
+		{"cancel", enum.CxlRejResponseTo_ORDER_CANCEL_REQUEST, map[quickfix.Tag]string{tag.Text: "order is closing"}, "Cancel rejected: order is closing"},
+		{"replace", enum.CxlRejResponseTo_ORDER_CANCEL_REPLACE_REQUEST, map[quickfix.Tag]string{tag.CxlRejReason: string(enum.CxlRejReason_TOO_LATE_TO_CANCEL)}, "Replace rejected: too late to cancel"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app, order := newTestApplication(t)
+			clOrdID := order.ClOrdID
+			pending := app.AssignNextClOrdID(order)
+
+			if err := app.FromApp(cancelReject(t, pending, clOrdID, tt.responseTo, tt.body), testSessionID); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := app.GetByClOrdID(pending); err == nil {
+				t.Error("want the ClOrdID of the rejected request unlinked")
+			}
+			if found, err := app.GetByClOrdID(clOrdID); err != nil || found != order {
+				t.Errorf("got %v %v, want the order under its ClOrdID", found, err)
+			}
+			if order.RejectReason != tt.want || order.OrdStatus != enum.OrdStatus_NEW || order.ClOrdID != clOrdID {
+				t.Errorf("got reason %q status %v clordid %v, want %q on the unchanged order", order.RejectReason, order.OrdStatus, order.ClOrdID, tt.want)
+			}
+
+			r := report{clOrdID: app.AssignNextClOrdID(order), origClOrdID: clOrdID, execType: enum.ExecType_CANCELED, ordStatus: enum.OrdStatus_CANCELED, cumQty: "0", leavesQty: "0", avgPx: "0"}
+			if err := app.FromApp(r.message(t), testSessionID); err != nil {
+				t.Fatal(err)
+			}
+			if order.RejectReason != "" {
+				t.Errorf("got reason %q, want it cleared once cancelled", order.RejectReason)
+			}
+		})
+	}
+}
@@ -2,11 +2,13 @@ package basic
 
 import (
 	"errors"
+	"strconv"
 	"time"
 
 	"github.com/quickfixgo/enum"
 	"github.com/quickfixgo/field"
 	"github.com/quickfixgo/traderui/oms"
+	"github.com/quickfixgo/traderui/rfq"
 	"github.com/quickfixgo/traderui/secmaster"
 
 	fix40nos "github.com/quickfixgo/fix40/newordersingle"
@@ -16,7 +18,28 @@ import (
 	fix44nos "github.com/quickfixgo/fix44/newordersingle"
 	fix50nos "github.com/quickfixgo/fix50/newordersingle"
 
+	fix42nol "github.com/quickfixgo/fix42/neworderlist"
+	fix43nol "github.com/quickfixgo/fix43/neworderlist"
+	fix44nol "github.com/quickfixgo/fix44/neworderlist"
+	fix50nol "github.com/quickfixgo/fix50/neworderlist"
+
+	fix44nml "github.com/quickfixgo/fix44/newordermultileg"
+	fix50nml "github.com/quickfixgo/fix50/newordermultileg"
+
+	fix42qr "github.com/quickfixgo/fix42/quoterequest"
+	fix43qr "github.com/quickfixgo/fix43/quoterequest"
+	fix44qr "github.com/quickfixgo/fix44/quoterequest"
+	fix50qr "github.com/quickfixgo/fix50/quoterequest"
+
 	fix42cxl "github.com/quickfixgo/fix42/ordercancelrequest"
+	fix43cxl "github.com/quickfixgo/fix43/ordercancelrequest"
+	fix44cxl "github.com/quickfixgo/fix44/ordercancelrequest"
+	fix50cxl "github.com/quickfixgo/fix50/ordercancelrequest"
+
+	fix42ocrr "github.com/quickfixgo/fix42/ordercancelreplacerequest"
+	fix43ocrr "github.com/quickfixgo/fix43/ordercancelreplacerequest"
+	fix44ocrr "github.com/quickfixgo/fix44/ordercancelreplacerequest"
+	fix50ocrr "github.com/quickfixgo/fix50/ordercancelreplacerequest"
 
 	"github.com/quickfixgo/quickfix"
 )
@@ -45,10 +68,85 @@ func (FIXFactory) NewOrderSingle(order oms.Order) (msg quickfix.Messagable, err
 	return
 }
 
+func (FIXFactory) NewOrderMultileg(order oms.Order) (msg quickfix.Messagable, err error) {
+	switch order.SessionID.BeginString {
+	case quickfix.BeginStringFIX44:
+		msg, err = nml44(order)
+	case quickfix.BeginStringFIXT11:
+		msg, err = nml50(order)
+	default:
+		err = errors.New("Unhandled BeginString")
+	}
+
+	return
+}
+
 func (FIXFactory) OrderCancelRequest(order oms.Order, clOrdID string) (msg quickfix.Messagable, err error) {
 	switch order.SessionID.BeginString {
 	case quickfix.BeginStringFIX42:
 		msg, err = cxl42(order, clOrdID)
+	case quickfix.BeginStringFIX43:
+		msg, err = cxl43(order, clOrdID)
+	case quickfix.BeginStringFIX44:
+		msg, err = cxl44(order, clOrdID)
+	case quickfix.BeginStringFIXT11:
+		msg, err = cxl50(order, clOrdID)
+	default:
+		err = errors.New("Unhandled BeginString")
+	}
+
+	return
+}
+
+// OrderCancelReplaceRequest amends order to its quantity and prices, replacing its ClOrdID with clOrdID
+func (FIXFactory) OrderCancelReplaceRequest(order oms.Order, clOrdID string) (msg quickfix.Messagable, err error) {
+	switch order.SessionID.BeginString {
+	case quickfix.BeginStringFIX42:
+		msg, err = ocrr42(order, clOrdID)
+	case quickfix.BeginStringFIX43:
+		msg, err = ocrr43(order, clOrdID)
+	case quickfix.BeginStringFIX44:
+		msg, err = ocrr44(order, clOrdID)
+	case quickfix.BeginStringFIXT11:
+		msg, err = ocrr50(order, clOrdID)
+	default:
+		err = errors.New("Unhandled BeginString")
+	}
+
+	return
+}
+
+func (FIXFactory) NewOrderList(listID string, orders []oms.Order) (msg quickfix.Messagable, err error) {
+	if len(orders) == 0 {
+		return nil, errors.New("Empty List")
+	}
+
+	switch orders[0].SessionID.BeginString {
+	case quickfix.BeginStringFIX42:
+		msg, err = list42(listID, orders)
+	case quickfix.BeginStringFIX43:
+		msg, err = list43(listID, orders)
+	case quickfix.BeginStringFIX44:
+		msg, err = list44(listID, orders)
+	case quickfix.BeginStringFIXT11:
+		msg, err = list50(listID, orders)
+	default:
+		err = errors.New("Unhandled BeginString")
+	}
+
+	return
+}
+
+func (FIXFactory) QuoteRequest(req rfq.Request) (msg quickfix.Messagable, err error) {
+	switch req.SessionID.BeginString {
+	case quickfix.BeginStringFIX42:
+		msg, err = qr42(req)
+	case quickfix.BeginStringFIX43:
+		msg, err = qr43(req)
+	case quickfix.BeginStringFIX44:
+		msg, err = qr44(req)
+	case quickfix.BeginStringFIXT11:
+		msg, err = qr50(req)
 	default:
 		err = errors.New("Unhandled BeginString")
 	}
@@ -64,8 +162,12 @@ func (FIXFactory) SecurityDefinitionRequest(req secmaster.SecurityDefinitionRequ
 func populateOrder(genMessage quickfix.Messagable, ord oms.Order) (quickfix.Messagable, error) {
 	msg := genMessage.ToMessage()
 
+	if ord.QuoteID != "" {
+		msg.Body.Set(field.NewQuoteID(ord.QuoteID))
+	}
+
 	switch ord.OrdType {
-	case enum.OrdType_LIMIT, enum.OrdType_STOP_LIMIT:
+	case enum.OrdType_LIMIT, enum.OrdType_STOP_LIMIT, enum.OrdType_PREVIOUSLY_QUOTED:
 		msg.Body.Set(field.NewPrice(ord.PriceDecimal, 2))
 	}
 
@@ -77,6 +179,219 @@ func populateOrder(genMessage quickfix.Messagable, ord oms.Order) (quickfix.Mess
 	return msg, nil
 }
 
+type fieldSetter interface {
+	Set(field quickfix.FieldWriter) *quickfix.FieldMap
+}
+
+func populateListOrder(grp fieldSetter, listSeqNo int, ord oms.Order) {
+	grp.Set(field.NewClOrdID(ord.ClOrdID))
+	grp.Set(field.NewListSeqNo(listSeqNo))
+	grp.Set(field.NewHandlInst("1"))
+	grp.Set(field.NewSymbol(ord.Symbol))
+	grp.Set(field.NewSide(ord.Side))
+	grp.Set(field.NewTransactTime(time.Now()))
+	grp.Set(field.NewOrderQty(ord.QuantityDecimal, 0))
+	grp.Set(field.NewOrdType(ord.OrdType))
+
+	if ord.Account != "" {
+		grp.Set(field.NewAccount(ord.Account))
+	}
+
+	switch ord.OrdType {
+	case enum.OrdType_LIMIT, enum.OrdType_STOP_LIMIT:
+		grp.Set(field.NewPrice(ord.PriceDecimal, 2))
+	}
+
+	switch ord.OrdType {
+	case enum.OrdType_STOP, enum.OrdType_STOP_LIMIT:
+		grp.Set(field.NewStopPx(ord.StopPriceDecimal, 2))
+	}
+}
+
+func populateLeg(grp fieldSetter, legRefID string, leg oms.Leg) {
+	grp.Set(field.NewLegSymbol(leg.Symbol))
+	grp.Set(field.NewLegRefID(legRefID))
+	grp.Set(field.NewLegSide(string(leg.Side)))
+	grp.Set(field.NewLegRatioQty(leg.RatioDecimal, 0))
+
+	if leg.SecurityType != "" {
+		grp.Set(field.NewLegSecurityType(string(leg.SecurityType)))
+	}
+
+	if leg.MaturityMonthYear != "" {
+		grp.Set(field.NewLegMaturityMonthYear(leg.MaturityMonthYear))
+	}
+
+	if leg.SecurityType == enum.SecurityType_OPTION {
+		switch leg.PutOrCall {
+		case enum.PutOrCall_CALL:
+			grp.Set(field.NewLegCFICode("OC"))
+		case enum.PutOrCall_PUT:
+			grp.Set(field.NewLegCFICode("OP"))
+		}
+		grp.Set(field.NewLegStrikePrice(leg.StrikePriceDecimal, 2))
+	}
+}
+
+func nml44(ord oms.Order) (quickfix.Messagable, error) {
+	nml := fix44nml.New(
+		field.NewClOrdID(ord.ClOrdID),
+		field.NewSide(ord.Side),
+		field.NewTransactTime(time.Now()),
+		field.NewOrdType(ord.OrdType),
+	)
+	nml.Set(field.NewSymbol(ord.Symbol))
+	nml.Set(field.NewHandlInst("1"))
+	nml.Set(field.NewOrderQty(ord.QuantityDecimal, 0))
+	nml.Set(field.NewMultiLegRptTypeReq(enum.MultiLegRptTypeReq_REPORT_BY_MULTILEG_SECURITY_AND_BY_INSTRUMENT_LEGS_BELONGING_TO_THE_MULTILEG_SECURITY))
+
+	grp := fix44nml.NewNoLegsRepeatingGroup()
+	for i, leg := range ord.Legs {
+		populateLeg(grp.Add(), strconv.Itoa(i+1), leg)
+	}
+	nml.SetNoLegs(grp)
+
+	return populateOrder(nml, ord)
+}
+
+func nml50(ord oms.Order) (quickfix.Messagable, error) {
+	nml := fix50nml.New(
+		field.NewClOrdID(ord.ClOrdID),
+		field.NewSide(ord.Side),
+		field.NewTransactTime(time.Now()),
+		field.NewOrdType(ord.OrdType),
+	)
+	nml.Set(field.NewSymbol(ord.Symbol))
+	nml.Set(field.NewHandlInst("1"))
+	nml.Set(field.NewOrderQty(ord.QuantityDecimal, 0))
+	nml.Set(field.NewMultiLegRptTypeReq(enum.MultiLegRptTypeReq_REPORT_BY_MULTILEG_SECURITY_AND_BY_INSTRUMENT_LEGS_BELONGING_TO_THE_MULTILEG_SECURITY))
+
+	grp := fix50nml.NewNoLegsRepeatingGroup()
+	for i, leg := range ord.Legs {
+		populateLeg(grp.Add(), strconv.Itoa(i+1), leg)
+	}
+	nml.SetNoLegs(grp)
+
+	return populateOrder(nml, ord)
+}
+
+func list42(listID string, orders []oms.Order) (quickfix.Messagable, error) {
+	list := fix42nol.New(
+		field.NewListID(listID),
+		field.NewBidType(enum.BidType_NO_BIDDING_PROCESS),
+		field.NewTotNoOrders(len(orders)),
+	)
+
+	grp := fix42nol.NewNoOrdersRepeatingGroup()
+	for i, ord := range orders {
+		populateListOrder(grp.Add(), i+1, ord)
+	}
+	list.SetNoOrders(grp)
+
+	return list, nil
+}
+
+func list43(listID string, orders []oms.Order) (quickfix.Messagable, error) {
+	list := fix43nol.New(
+		field.NewListID(listID),
+		field.NewBidType(enum.BidType_NO_BIDDING_PROCESS),
+		field.NewTotNoOrders(len(orders)),
+	)
+
+	grp := fix43nol.NewNoOrdersRepeatingGroup()
+	for i, ord := range orders {
+		populateListOrder(grp.Add(), i+1, ord)
+	}
+	list.SetNoOrders(grp)
+
+	return list, nil
+}
+
+func list44(listID string, orders []oms.Order) (quickfix.Messagable, error) {
+	list := fix44nol.New(
+		field.NewListID(listID),
+		field.NewBidType(enum.BidType_NO_BIDDING_PROCESS),
+		field.NewTotNoOrders(len(orders)),
+	)
+
+	grp := fix44nol.NewNoOrdersRepeatingGroup()
+	for i, ord := range orders {
+		populateListOrder(grp.Add(), i+1, ord)
+	}
+	list.SetNoOrders(grp)
+
+	return list, nil
+}
+
+func list50(listID string, orders []oms.Order) (quickfix.Messagable, error) {
+	list := fix50nol.New(
+		field.NewListID(listID),
+		field.NewBidType(enum.BidType_NO_BIDDING_PROCESS),
+		field.NewTotNoOrders(len(orders)),
+	)
+
+	grp := fix50nol.NewNoOrdersRepeatingGroup()
+	for i, ord := range orders {
+		populateListOrder(grp.Add(), i+1, ord)
+	}
+	list.SetNoOrders(grp)
+
+	return list, nil
+}
+
+func populateRelatedSym(grp fieldSetter, req rfq.Request) {
+	grp.Set(field.NewSymbol(req.Symbol))
+	grp.Set(field.NewOrderQty(req.QuantityDecimal, 0))
+
+	if req.Side != "" {
+		grp.Set(field.NewSide(req.Side))
+	}
+
+	if req.SecurityType != "" {
+		grp.Set(field.NewSecurityType(req.SecurityType))
+	}
+}
+
+func qr42(req rfq.Request) (quickfix.Messagable, error) {
+	qr := fix42qr.New(field.NewQuoteReqID(req.QuoteReqID))
+
+	grp := fix42qr.NewNoRelatedSymRepeatingGroup()
+	populateRelatedSym(grp.Add(), req)
+	qr.SetNoRelatedSym(grp)
+
+	return qr, nil
+}
+
+func qr43(req rfq.Request) (quickfix.Messagable, error) {
+	qr := fix43qr.New(field.NewQuoteReqID(req.QuoteReqID))
+
+	grp := fix43qr.NewNoRelatedSymRepeatingGroup()
+	populateRelatedSym(grp.Add(), req)
+	qr.SetNoRelatedSym(grp)
+
+	return qr, nil
+}
+
+func qr44(req rfq.Request) (quickfix.Messagable, error) {
+	qr := fix44qr.New(field.NewQuoteReqID(req.QuoteReqID))
+
+	grp := fix44qr.NewNoRelatedSymRepeatingGroup()
+	populateRelatedSym(grp.Add(), req)
+	qr.SetNoRelatedSym(grp)
+
+	return qr, nil
+}
+
+func qr50(req rfq.Request) (quickfix.Messagable, error) {
+	qr := fix50qr.New(field.NewQuoteReqID(req.QuoteReqID))
+
+	grp := fix50qr.NewNoRelatedSymRepeatingGroup()
+	populateRelatedSym(grp.Add(), req)
+	qr.SetNoRelatedSym(grp)
+
+	return qr, nil
+}
+
 func nos40(ord oms.Order) (quickfix.Messagable, error) {
 	nos := fix40nos.New(
 		field.NewClOrdID(ord.ClOrdID),
@@ -129,6 +444,109 @@ func cxl42(ord oms.Order, clOrdID string) (quickfix.Messagable, error) {
 	return cxl, nil
 }
 
+func cxl43(ord oms.Order, clOrdID string) (quickfix.Messagable, error) {
+	cxl := fix43cxl.New(
+		field.NewOrigClOrdID(ord.ClOrdID),
+		field.NewClOrdID(clOrdID),
+		field.NewSide(ord.Side),
+		field.NewTransactTime(time.Now()),
+	)
+	cxl.Set(field.NewSymbol(ord.Symbol))
+	cxl.Set(field.NewOrderQty(ord.QuantityDecimal, 0))
+
+	return cxl, nil
+}
+
+func cxl44(ord oms.Order, clOrdID string) (quickfix.Messagable, error) {
+	cxl := fix44cxl.New(
+		field.NewOrigClOrdID(ord.ClOrdID),
+		field.NewClOrdID(clOrdID),
+		field.NewSide(ord.Side),
+		field.NewTransactTime(time.Now()),
+	)
+	cxl.Set(field.NewSymbol(ord.Symbol))
+	cxl.Set(field.NewOrderQty(ord.QuantityDecimal, 0))
+
+	return cxl, nil
+}
+
+func cxl50(ord oms.Order, clOrdID string) (quickfix.Messagable, error) {
+	cxl := fix50cxl.New(
+		field.NewOrigClOrdID(ord.ClOrdID),
+		field.NewClOrdID(clOrdID),
+		field.NewSide(ord.Side),
+		field.NewTransactTime(time.Now()),
+	)
+	cxl.Set(field.NewSymbol(ord.Symbol))
+	cxl.Set(field.NewOrderQty(ord.QuantityDecimal, 0))
+
+	return cxl, nil
+}
+
+func populateReplace(genMessage quickfix.Messagable, ord oms.Order) (quickfix.Messagable, error) {
+	msg := genMessage.ToMessage()
+	msg.Body.Set(field.NewOrderQty(ord.QuantityDecimal, 0))
+	if ord.Account != "" {
+		msg.Body.Set(field.NewAccount(ord.Account))
+	}
+
+	return populateOrder(msg, ord)
+}
+
+func ocrr42(ord oms.Order, clOrdID string) (quickfix.Messagable, error) {
+	ocrr := fix42ocrr.New(
+		field.NewOrigClOrdID(ord.ClOrdID),
+		field.NewClOrdID(clOrdID),
+		field.NewHandlInst("1"),
+		field.NewSymbol(ord.Symbol),
+		field.NewSide(ord.Side),
+		field.NewTransactTime(time.Now()),
+		field.NewOrdType(ord.OrdType),
+	)
+
+	return populateReplace(ocrr, ord)
+}
+
+func ocrr43(ord oms.Order, clOrdID string) (quickfix.Messagable, error) {
+	ocrr := fix43ocrr.New(
+		field.NewOrigClOrdID(ord.ClOrdID),
+		field.NewClOrdID(clOrdID),
+		field.NewHandlInst("1"),
+		field.NewSide(ord.Side),
+		field.NewTransactTime(time.Now()),
+		field.NewOrdType(ord.OrdType),
+	)
+	ocrr.Set(field.NewSymbol(ord.Symbol))
+
+	return populateReplace(ocrr, ord)
+}
+
+func ocrr44(ord oms.Order, clOrdID string) (quickfix.Messagable, error) {
+	ocrr := fix44ocrr.New(
+		field.NewOrigClOrdID(ord.ClOrdID),
+		field.NewClOrdID(clOrdID),
+		field.NewSide(ord.Side),
+		field.NewTransactTime(time.Now()),
+		field.NewOrdType(ord.OrdType),
+	)
+	ocrr.Set(field.NewSymbol(ord.Symbol))
+
+	return populateReplace(ocrr, ord)
+}
+
+func ocrr50(ord oms.Order, clOrdID string) (quickfix.Messagable, error) {
+	ocrr := fix50ocrr.New(
+		field.NewOrigClOrdID(ord.ClOrdID),
+		field.NewClOrdID(clOrdID),
+		field.NewSide(ord.Side),
+		field.NewTransactTime(time.Now()),
+		field.NewOrdType(ord.OrdType),
+	)
+	ocrr.Set(field.NewSymbol(ord.Symbol))
+
+	return populateReplace(ocrr, ord)
+}
+
 func nos43(ord oms.Order) (quickfix.Messagable, error) {
 	nos := fix43nos.New(
 		field.NewClOrdID(ord.ClOrdID),
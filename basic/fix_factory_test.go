@@ -0,0 +1,184 @@
+package basic
+
+import (
+	"testing"
+
+	"github.com/quickfixgo/enum"
+	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/tag"
+	"github.com/quickfixgo/traderui/oms"
+)
+
+// testOrder returns an initialized order on a session of beginString
+func testOrder(t *testing.T, beginString string, ordType enum.OrdType) oms.Order {
+	t.Helper()
+
+	order := oms.Order{
+		ClOrdID:   "100",
+		Symbol:    "IBM",
+		Side:      enum.Side_BUY,
+		Quantity:  "250",
+		OrdType:   ordType,
+		Price:     "101.25",
+		StopPrice: "99.5",
+		Account:   "ACCT1",
+		SessionID: quickfix.SessionID{BeginString: beginString, SenderCompID: "TW", TargetCompID: "ISLD"},
+	}
+	if err := order.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	return order
+}
+
+// assertTags checks that msg carries want, an empty value asserting that the tag is absent and
+// * that it is present with any value
+func assertTags(t *testing.T, m quickfix.Messagable, want map[quickfix.Tag]string) {
+	t.Helper()
+
+	msg := m.ToMessage()
+	for tg, value := range want {
+		var got string
+		var err quickfix.MessageRejectError
+		switch tg {
+		case tag.BeginString, tag.MsgType:
+			got, err = msg.Header.GetString(tg)
+		default:
+			got, err = msg.Body.GetString(tg)
+		}
+
+		switch {
+		case value == "" && err == nil:
+			t.Errorf("tag %v: got %q, want absent", tg, got)
+		case value != "" && err != nil:
+			t.Errorf("tag %v: %v", tg, err)
+		case value != "*" && got != value:
+			t.Errorf("tag %v: got %q, want %q", tg, got, value)
+		}
+	}
+}
+
+func TestNewOrderSingle(t *testing.T) {
+	tests := []struct {
+		beginString string
+		ordType     enum.OrdType
+		want        map[quickfix.Tag]string
+	}{
+		{quickfix.BeginStringFIX40, enum.OrdType_MARKET, map[quickfix.Tag]string{
+			tag.BeginString: "FIX.4.0", tag.MsgType: "D", tag.ClOrdID: "100", tag.HandlInst: "1", tag.Symbol: "IBM",
+			tag.Side: "1", tag.OrderQty: "250", tag.OrdType: "1", tag.Price: "", tag.StopPx: "", tag.TransactTime: "",
+		}},
+		{quickfix.BeginStringFIX41, enum.OrdType_LIMIT, map[quickfix.Tag]string{
+			tag.BeginString: "FIX.4.1", tag.MsgType: "D", tag.ClOrdID: "100", tag.HandlInst: "1", tag.Symbol: "IBM",
+			tag.Side: "1", tag.OrderQty: "250", tag.OrdType: "2", tag.Price: "101.25", tag.StopPx: "", tag.TransactTime: "",
+		}},
+		{quickfix.BeginStringFIX42, enum.OrdType_STOP, map[quickfix.Tag]string{
+			tag.BeginString: "FIX.4.2", tag.MsgType: "D", tag.ClOrdID: "100", tag.HandlInst: "1", tag.Symbol: "IBM",
+			tag.Side: "1", tag.OrderQty: "250", tag.OrdType: "3", tag.Price: "", tag.StopPx: "99.50", tag.TransactTime: "*",
+		}},
+		{quickfix.BeginStringFIX43, enum.OrdType_STOP_LIMIT, map[quickfix.Tag]string{
+			tag.BeginString: "FIX.4.3", tag.MsgType: "D", tag.ClOrdID: "100", tag.HandlInst: "1", tag.Symbol: "IBM",
+			tag.Side: "1", tag.OrderQty: "250", tag.OrdType: "4", tag.Price: "101.25", tag.StopPx: "99.50", tag.TransactTime: "*",
+		}},
+		{quickfix.BeginStringFIX44, enum.OrdType_LIMIT, map[quickfix.Tag]string{
+			tag.BeginString: "FIX.4.4", tag.MsgType: "D", tag.ClOrdID: "100", tag.HandlInst: "1", tag.Symbol: "IBM",
+			tag.Side: "1", tag.OrderQty: "250", tag.OrdType: "2", tag.Price: "101.25", tag.TransactTime: "*",
+		}},
+		{quickfix.BeginStringFIXT11, enum.OrdType_LIMIT, map[quickfix.Tag]string{
+			tag.BeginString: "FIXT.1.1", tag.MsgType: "D", tag.ClOrdID: "100", tag.HandlInst: "1", tag.Symbol: "IBM",
+			tag.Side: "1", tag.OrderQty: "250", tag.OrdType: "2", tag.Price: "101.25", tag.TransactTime: "*",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.beginString, func(t *testing.T) {
+			msg, err := FIXFactory{}.NewOrderSingle(testOrder(t, tt.beginString, tt.ordType))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assertTags(t, msg, tt.want)
+		})
+	}
+}
+
+func TestNewOrderSingleUnhandledBeginString(t *testing.T) {
+	if _, err := (FIXFactory{}).NewOrderSingle(testOrder(t, "FIX.9.9", enum.OrdType_MARKET)); err == nil {
+		t.Error("expected an error")
+	}
+}
+
+func TestOrderCancelRequest(t *testing.T) {
+	tests := []struct {
+		beginString string
+		want        map[quickfix.Tag]string
+		wantErr     bool
+	}{
+		{quickfix.BeginStringFIX42, map[quickfix.Tag]string{
+			tag.BeginString: "FIX.4.2", tag.MsgType: "F", tag.OrigClOrdID: "100", tag.ClOrdID: "101",
+			tag.Symbol: "IBM", tag.Side: "1", tag.TransactTime: "*",
+		}, false},
+		{quickfix.BeginStringFIX43, map[quickfix.Tag]string{
+			tag.BeginString: "FIX.4.3", tag.MsgType: "F", tag.OrigClOrdID: "100", tag.ClOrdID: "101",
+			tag.Symbol: "IBM", tag.Side: "1", tag.OrderQty: "250", tag.TransactTime: "*",
+		}, false},
+		{quickfix.BeginStringFIX44, map[quickfix.Tag]string{
+			tag.BeginString: "FIX.4.4", tag.MsgType: "F", tag.OrigClOrdID: "100", tag.ClOrdID: "101",
+			tag.Symbol: "IBM", tag.Side: "1", tag.OrderQty: "250", tag.TransactTime: "*",
+		}, false},
+		{quickfix.BeginStringFIXT11, map[quickfix.Tag]string{
+			tag.BeginString: "FIXT.1.1", tag.MsgType: "F", tag.OrigClOrdID: "100", tag.ClOrdID: "101",
+			tag.Symbol: "IBM", tag.Side: "1", tag.OrderQty: "250", tag.TransactTime: "*",
+		}, false},
+		{quickfix.BeginStringFIX41, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.beginString, func(t *testing.T) {
+			msg, err := FIXFactory{}.OrderCancelRequest(testOrder(t, tt.beginString, enum.OrdType_LIMIT), "101")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+			if err == nil {
+				assertTags(t, msg, tt.want)
+			}
+		})
+	}
+}
+
+func TestOrderCancelReplaceRequest(t *testing.T) {
+	tests := []struct {
+		beginString string
+		want        map[quickfix.Tag]string
+	}{
+		{quickfix.BeginStringFIX42, map[quickfix.Tag]string{
+			tag.BeginString: "FIX.4.2", tag.HandlInst: "1",
+		}},
+		{quickfix.BeginStringFIX43, map[quickfix.Tag]string{
+			tag.BeginString: "FIX.4.3", tag.HandlInst: "1",
+		}},
+		{quickfix.BeginStringFIX44, map[quickfix.Tag]string{
+			tag.BeginString: "FIX.4.4", tag.HandlInst: "",
+		}},
+		{quickfix.BeginStringFIXT11, map[quickfix.Tag]string{
+			tag.BeginString: "FIXT.1.1", tag.HandlInst: "",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.beginString, func(t *testing.T) {
+			msg, err := FIXFactory{}.OrderCancelReplaceRequest(testOrder(t, tt.beginString, enum.OrdType_LIMIT), "101")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for tg, value := range map[quickfix.Tag]string{
+				tag.MsgType: "G", tag.OrigClOrdID: "100", tag.ClOrdID: "101", tag.Symbol: "IBM", tag.Side: "1",
+				tag.OrderQty: "250", tag.OrdType: "2", tag.Price: "101.25", tag.Account: "ACCT1",
+			} {
+				tt.want[tg] = value
+			}
+			assertTags(t, msg, tt.want)
+		})
+	}
+}
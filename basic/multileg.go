@@ -0,0 +1,134 @@
+package basic
+
+import (
+	"log"
+	"strconv"
+
+	"github.com/quickfixgo/enum"
+	"github.com/quickfixgo/field"
+	"github.com/quickfixgo/tag"
+	"github.com/quickfixgo/traderui/oms"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+// legsGroupTemplate covers the NoLegs fields found on multileg execution reports
+var legsGroupTemplate = quickfix.GroupTemplate{
+	quickfix.GroupElement(tag.LegSymbol),
+	quickfix.GroupElement(tag.LegSecurityID),
+	quickfix.GroupElement(tag.LegSecurityIDSource),
+	quickfix.GroupElement(tag.LegCFICode),
+	quickfix.GroupElement(tag.LegSecurityType),
+	quickfix.GroupElement(tag.LegMaturityMonthYear),
+	quickfix.GroupElement(tag.LegMaturityDate),
+	quickfix.GroupElement(tag.LegStrikePrice),
+	quickfix.GroupElement(tag.LegPutOrCall),
+	quickfix.GroupElement(tag.LegContractMultiplier),
+	quickfix.GroupElement(tag.LegSecurityDesc),
+	quickfix.GroupElement(tag.LegRatioQty),
+	quickfix.GroupElement(tag.LegSide),
+	quickfix.GroupElement(tag.LegCurrency),
+	quickfix.GroupElement(tag.LegQty),
+	quickfix.GroupElement(tag.LegOrderQty),
+	quickfix.GroupElement(tag.LegPositionEffect),
+	quickfix.GroupElement(tag.LegCoveredOrUncovered),
+	quickfix.GroupElement(tag.LegRefID),
+	quickfix.GroupElement(tag.LegPrice),
+	quickfix.GroupElement(tag.LegSettlType),
+	quickfix.GroupElement(tag.LegSettlDate),
+	quickfix.GroupElement(tag.LegLastPx),
+	quickfix.GroupElement(tag.LegLastQty),
+}
+
+// saveLegExecutions records one execution per leg of a multileg execution report that carries
+// leg fill prices. saved is false if the report has no leg fills.
+func (a *FIXApplication) saveLegExecutions(msg *quickfix.Message, order *oms.Order, lastShares field.LastSharesField) (saved bool, err quickfix.MessageRejectError) {
+	legs := quickfix.NewRepeatingGroup(tag.NoLegs, legsGroupTemplate)
+	if err := msg.Body.GetGroup(legs); err != nil {
+		log.Printf("[ERROR] could not parse legs of %v: %v", order.ClOrdID, err)
+		return false, nil
+	}
+
+	for i := 0; i < legs.Len(); i++ {
+		grp := legs.Get(i)
+		if !grp.Has(tag.LegLastPx) {
+			continue
+		}
+
+		exec := new(oms.Execution)
+		exec.Session = order.Session
+		exec.Account = order.Account
+		exec.LegRefID, _ = grp.GetString(tag.LegRefID)
+		leg := legByRefID(order, exec.LegRefID, i)
+
+		exec.Symbol = leg.Symbol
+		if legSymbol, err := grp.GetString(tag.LegSymbol); err == nil {
+			exec.Symbol = legSymbol
+		}
+
+		exec.Side = leg.Side
+		if legSide, err := grp.GetString(tag.LegSide); err == nil {
+			exec.Side = enum.Side(legSide)
+		}
+
+		if exec.Price, err = grp.GetString(tag.LegLastPx); err != nil {
+			return saved, err
+		}
+
+		var legLastQty field.LegLastQtyField
+		switch {
+		case grp.Has(tag.LegLastQty):
+			if err = grp.Get(&legLastQty); err != nil {
+				return saved, err
+			}
+			exec.Quantity = legLastQty.String()
+		default:
+			exec.Quantity = lastShares.Value().Mul(leg.RatioDecimal).String()
+		}
+
+		_ = a.SaveExecution(exec)
+		saved = true
+	}
+
+	return saved, nil
+}
+
+// saveLegReportExecution records the fill of an execution report on an individual leg
+func (a *FIXApplication) saveLegReportExecution(msg *quickfix.Message, order *oms.Order, lastShares field.LastSharesField, price field.LastPxField) quickfix.MessageRejectError {
+	exec := new(oms.Execution)
+	exec.Session = order.Session
+	exec.Account = order.Account
+	exec.Quantity = lastShares.String()
+	exec.Price = price.String()
+	exec.Symbol = order.Symbol
+	exec.Side = order.Side
+
+	if symbol, err := msg.Body.GetString(tag.Symbol); err == nil {
+		exec.Symbol = symbol
+	}
+
+	var side field.SideField
+	if err := msg.Body.Get(&side); err == nil {
+		exec.Side = side.Value()
+	}
+
+	if legRefID, err := msg.Body.GetString(tag.LegRefID); err == nil {
+		exec.LegRefID = legRefID
+	}
+
+	_ = a.SaveExecution(exec)
+	return nil
+}
+
+// legByRefID finds the order leg for a LegRefID, falling back to the leg position
+func legByRefID(order *oms.Order, legRefID string, i int) oms.Leg {
+	if n, err := strconv.Atoi(legRefID); err == nil && n > 0 && n <= len(order.Legs) {
+		return order.Legs[n-1]
+	}
+
+	if i < len(order.Legs) {
+		return order.Legs[i]
+	}
+
+	return oms.Leg{}
+}
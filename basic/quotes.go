@@ -0,0 +1,134 @@
+package basic
+
+import (
+	"log"
+	"time"
+
+	"github.com/quickfixgo/enum"
+	"github.com/quickfixgo/field"
+	"github.com/quickfixgo/tag"
+	"github.com/quickfixgo/traderui/rfq"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+func (a *FIXApplication) onQuote(msg *quickfix.Message, sessionID quickfix.SessionID) quickfix.MessageRejectError {
+	if a.RFQs == nil {
+		return quickfix.UnsupportedMessageType()
+	}
+
+	a.RFQs.Lock()
+	defer a.RFQs.Unlock()
+
+	var quoteID field.QuoteIDField
+	if err := msg.Body.Get(&quoteID); err != nil {
+		return err
+	}
+
+	quoteReqID, _ := msg.Body.GetString(tag.QuoteReqID)
+	req, err := a.RFQs.GetByQuoteReqID(quoteReqID)
+	if err != nil {
+		log.Printf("[ERROR] err= %v", err)
+		return nil
+	}
+
+	quote := &rfq.Quote{
+		QuoteID:  quoteID.String(),
+		Received: time.Now(),
+	}
+	quote.BidPx, _ = msg.Body.GetString(tag.BidPx)
+	quote.OfferPx, _ = msg.Body.GetString(tag.OfferPx)
+	quote.BidSize, _ = msg.Body.GetString(tag.BidSize)
+	quote.OfferSize, _ = msg.Body.GetString(tag.OfferSize)
+
+	if msg.Body.Has(tag.ValidUntilTime) {
+		var validUntil field.ValidUntilTimeField
+		if err := msg.Body.Get(&validUntil); err != nil {
+			return err
+		}
+		quote.ValidUntil = validUntil.Value()
+	}
+
+	a.RFQs.AddQuote(req, quote)
+	return nil
+}
+
+func (a *FIXApplication) onQuoteRequestReject(msg *quickfix.Message, sessionID quickfix.SessionID) quickfix.MessageRejectError {
+	if a.RFQs == nil {
+		return quickfix.UnsupportedMessageType()
+	}
+
+	a.RFQs.Lock()
+	defer a.RFQs.Unlock()
+
+	var quoteReqID field.QuoteReqIDField
+	if err := msg.Body.Get(&quoteReqID); err != nil {
+		return err
+	}
+
+	req, err := a.RFQs.GetByQuoteReqID(quoteReqID.String())
+	if err != nil {
+		log.Printf("[ERROR] err= %v", err)
+		return nil
+	}
+
+	req.Status = rfq.Rejected
+	req.RejectReason, _ = msg.Body.GetString(tag.QuoteRequestRejectReason)
+	if text, err := msg.Body.GetString(tag.Text); err == nil {
+		req.RejectReason = text
+	}
+
+	return nil
+}
+
+func (a *FIXApplication) onQuoteCancel(msg *quickfix.Message, sessionID quickfix.SessionID) quickfix.MessageRejectError {
+	if a.RFQs == nil {
+		return quickfix.UnsupportedMessageType()
+	}
+
+	a.RFQs.Lock()
+	defer a.RFQs.Unlock()
+
+	var cancelType field.QuoteCancelTypeField
+	if err := msg.Body.Get(&cancelType); err != nil {
+		return err
+	}
+
+	if cancelType.Value() == enum.QuoteCancelType_CANCEL_ALL_QUOTES {
+		for _, req := range a.RFQs.GetAll() {
+			if req.SessionID == sessionID {
+				cancelQuotes(req, "")
+			}
+		}
+		return nil
+	}
+
+	quoteID, _ := msg.Body.GetString(tag.QuoteID)
+	quoteReqID, _ := msg.Body.GetString(tag.QuoteReqID)
+
+	req, err := a.RFQs.GetByQuoteReqID(quoteReqID)
+	if err != nil {
+		req, err = a.RFQs.GetByQuoteID(quoteID)
+	}
+
+	if err != nil {
+		log.Printf("[ERROR] err= %v", err)
+		return nil
+	}
+
+	if _, err := req.GetQuote(quoteID); err != nil {
+		quoteID = ""
+	}
+
+	cancelQuotes(req, quoteID)
+	return nil
+}
+
+// cancelQuotes cancels the quote with quoteID, or every quote of req if quoteID is empty
+func cancelQuotes(req *rfq.Request, quoteID string) {
+	for _, q := range req.Quotes {
+		if quoteID == "" || q.QuoteID == quoteID {
+			q.Cancelled = true
+		}
+	}
+}
@@ -0,0 +1,254 @@
+package basic
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/quickfixgo/enum"
+	"github.com/quickfixgo/tag"
+	"github.com/quickfixgo/traderui/oms"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+// Recordings are plain text, one entry per line:
+//
+//	<offset> <kind> <session> <data>
+//
+// offset is the time since the recording started as a Go duration. An "in" entry is an inbound
+// application message with SOH written as |, an "order" entry is the json of an order as it was
+// before its first execution report, or the first report on one of its children, and a "link"
+// entry is an order id and a further ClOrdID of that order, e.g. of a cancel. Lines starting with
+// # are comments.
+const (
+	recordIn    = "in"
+	recordOrder = "order"
+	recordLink  = "link"
+)
+
+// Recorder is the FIXApplication, recording the inbound application messages of every session
+// along with the orders they refer to, so that Replay can reproduce the OrderManager
+type Recorder struct {
+	*FIXApplication
+
+	mu       sync.Mutex
+	w        io.Writer
+	start    time.Time
+	orders   map[int]bool
+	clOrdIDs map[string]bool
+}
+
+// NewRecorder returns a Recorder of app writing to w
+func NewRecorder(app *FIXApplication, w io.Writer) (*Recorder, error) {
+	r := &Recorder{
+		FIXApplication: app,
+		w:              w,
+		start:          time.Now(),
+		orders:         make(map[int]bool),
+		clOrdIDs:       make(map[string]bool),
+	}
+
+	_, err := fmt.Fprintf(w, "# traderui recording started %v\n", r.start.UTC().Format(time.RFC3339Nano))
+	return r, err
+}
+
+func (r *Recorder) write(offset time.Duration, kind string, sessionID quickfix.SessionID, data string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, _ = fmt.Fprintf(r.w, "%v %v %v %v\n", offset, kind, sessionID, data)
+}
+
+// FromApp records the message, preceded by the order it reports on if not yet recorded, and
+// passes it on to the FIXApplication
+func (r *Recorder) FromApp(msg *quickfix.Message, sessionID quickfix.SessionID) quickfix.MessageRejectError {
+	offset := time.Since(r.start)
+
+	if msgType, _ := msg.MsgType(); enum.MsgType(msgType) == enum.MsgType_EXECUTION_REPORT {
+		if clOrdID, err := msg.Body.GetString(tag.ClOrdID); err == nil {
+			r.recordOrder(offset, sessionID, clOrdID)
+		}
+	}
+
+	r.write(offset, recordIn, sessionID, strings.ReplaceAll(msg.String(), "\x01", "|"))
+
+	return r.FIXApplication.FromApp(msg, sessionID)
+}
+
+// recordOrder records the order of clOrdID the first time it is reported on, after its parents
+// such as algo parent orders, which are never reported on themselves, and the ClOrdID the first
+// time it is used
+func (r *Recorder) recordOrder(offset time.Duration, sessionID quickfix.SessionID, clOrdID string) {
+	r.Lock()
+	defer r.Unlock()
+
+	order, err := r.GetByClOrdID(clOrdID)
+	if err != nil || order.External {
+		return
+	}
+
+	lineage := []*oms.Order{order}
+	for o := order; o.ParentID != 0; {
+		if o, err = r.Get(o.ParentID); err != nil {
+			break
+		}
+		lineage = append(lineage, o)
+	}
+
+	r.mu.Lock()
+	newClOrdID := !r.clOrdIDs[clOrdID]
+	r.clOrdIDs[clOrdID] = true
+	var newOrders []*oms.Order
+	for i := len(lineage) - 1; i >= 0; i-- {
+		if o := lineage[i]; !r.orders[o.ID] {
+			r.orders[o.ID], r.clOrdIDs[o.ClOrdID] = true, true
+			newOrders = append(newOrders, o)
+		}
+	}
+	r.mu.Unlock()
+
+	for _, o := range newOrders {
+		b, _ := json.Marshal(o)
+		r.write(offset, recordOrder, orderSessionID(o, sessionID), string(b))
+	}
+	if newClOrdID && clOrdID != order.ClOrdID {
+		r.write(offset, recordLink, sessionID, fmt.Sprintf("%v %v", order.ID, clOrdID))
+	}
+}
+
+// orderSessionID is the session of the order, sessionID if the order has none
+func orderSessionID(order *oms.Order, sessionID quickfix.SessionID) quickfix.SessionID {
+	if order.SessionID.BeginString == "" {
+		return sessionID
+	}
+
+	return order.SessionID
+}
+
+// ParseSessionID parses the string form of a session id, e.g. FIX.4.2:TW->ISLD
+func ParseSessionID(s string) (quickfix.SessionID, error) {
+	var sessionID quickfix.SessionID
+
+	beginString, rest, ok := strings.Cut(s, ":")
+	ids, qualifier, _ := strings.Cut(rest, ":")
+	sender, target, ok2 := strings.Cut(ids, "->")
+	if !ok || !ok2 {
+		return sessionID, fmt.Errorf("invalid session %q", s)
+	}
+
+	senderIDs := append(strings.SplitN(sender, "/", 3), "", "")
+	targetIDs := append(strings.SplitN(target, "/", 3), "", "")
+
+	sessionID.BeginString, sessionID.Qualifier = beginString, qualifier
+	sessionID.SenderCompID, sessionID.SenderSubID, sessionID.SenderLocationID = senderIDs[0], senderIDs[1], senderIDs[2]
+	sessionID.TargetCompID, sessionID.TargetSubID, sessionID.TargetLocationID = targetIDs[0], targetIDs[1], targetIDs[2]
+
+	return sessionID, nil
+}
+
+// Replay feeds a recording into app as it was received, restoring the recorded orders to its
+// OrderManager first. speed scales the recorded timing, 0 replays without waiting. Orders of drop
+// copy sessions are created again by app, possibly with other ids.
+func Replay(ctx context.Context, r io.Reader, app *FIXApplication, speed float64) error {
+	start := time.Now()
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<20)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		if err := replayEntry(ctx, text, app, start, speed); err != nil {
+			return fmt.Errorf("line %v: %v", line, err)
+		}
+	}
+
+	return scanner.Err()
+}
+
+func replayEntry(ctx context.Context, text string, app *FIXApplication, start time.Time, speed float64) error {
+	entry := strings.SplitN(text, " ", 4)
+	if len(entry) != 4 {
+		return fmt.Errorf("expected <offset> <kind> <session> <data>")
+	}
+
+	offset, err := time.ParseDuration(entry[0])
+	if err != nil {
+		return err
+	}
+
+	sessionID, ok := app.SessionIDs[entry[2]]
+	if !ok {
+		if sessionID, err = ParseSessionID(entry[2]); err != nil {
+			return err
+		}
+	}
+
+	if speed > 0 {
+		wait := time.NewTimer(time.Until(start.Add(time.Duration(float64(offset) / speed))))
+		defer wait.Stop()
+
+		select {
+		case <-wait.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	switch entry[1] {
+	case recordIn:
+		msg := quickfix.NewMessage()
+		if err := quickfix.ParseMessage(msg, bytes.NewBufferString(strings.ReplaceAll(entry[3], "|", "\x01"))); err != nil {
+			return err
+		}
+		// the live session would have rejected the message and carried on
+		if reject := app.FromApp(msg, sessionID); reject != nil {
+			log.Printf("[WARN] replay: %v rejected: %v", sessionID, reject)
+		}
+
+	case recordOrder:
+		order := new(oms.Order)
+		if err := json.Unmarshal([]byte(entry[3]), order); err != nil {
+			return err
+		}
+		if err := order.Init(); err != nil {
+			return err
+		}
+		order.SessionID = sessionID
+
+		app.Lock()
+		defer app.Unlock()
+		return app.Restore(order)
+
+	case recordLink:
+		idText, clOrdID, _ := strings.Cut(entry[3], " ")
+		id, err := strconv.Atoi(idText)
+		if err != nil {
+			return err
+		}
+
+		app.Lock()
+		defer app.Unlock()
+		order, err := app.Get(id)
+		if err != nil {
+			return err
+		}
+		app.AssignClOrdID(order, clOrdID)
+
+	default:
+		return fmt.Errorf("unknown entry %q", entry[1])
+	}
+
+	return nil
+}
@@ -0,0 +1,136 @@
+package basic
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+
+	"github.com/quickfixgo/enum"
+	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/traderui/oms"
+)
+
+func TestReplayRecording(t *testing.T) {
+	f, err := os.Open("testdata/partial_fill_cancel.rec")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	app := &FIXApplication{SessionIDs: make(map[string]quickfix.SessionID), OrderManager: oms.NewOrderManager(new(ClOrdIDGenerator))}
+	if err := Replay(context.Background(), f, app, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	orders := app.GetAll()
+	if len(orders) != 2 {
+		t.Fatalf("got %v orders, want 2", len(orders))
+	}
+
+	if o := orders[0]; o.OrdStatus != enum.OrdStatus_CANCELED || o.Closed != "100" || o.Open != "0" || o.User != "alice" || o.SessionID != testSessionID {
+		t.Errorf("order 1: got %+v", *o)
+	}
+	if o := orders[1]; o.OrdStatus != enum.OrdStatus_REJECTED || o.User != "bob" {
+		t.Errorf("order 2: got %+v", *o)
+	}
+	if executions := app.GetAllExecutions(); len(executions) != 1 || executions[0].Quantity != "100" || executions[0].Price != "101.25" {
+		t.Errorf("got executions %v, want one of 100 at 101.25", executions)
+	}
+}
+
+func TestRecordReplay(t *testing.T) {
+	app, order := newTestApplication(t)
+	var recording bytes.Buffer
+	recorder, err := NewRecorder(app, &recording)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	second := testOrder(t, testSessionID.BeginString, enum.OrdType_MARKET)
+	second.Session = testSessionID.String()
+	_ = app.Save(&second)
+	cxlClOrdID := app.AssignNextClOrdID(order)
+
+	// reports on the second order arrive first, so orders are restored out of id order
+	for _, r := range []report{
+		{clOrdID: second.ClOrdID, execType: enum.ExecType_FILL, ordStatus: enum.OrdStatus_FILLED, cumQty: "250", leavesQty: "0", avgPx: "99", lastShares: "250", lastPx: "99"},
+		{clOrdID: order.ClOrdID, execType: enum.ExecType_PARTIAL_FILL, ordStatus: enum.OrdStatus_PARTIALLY_FILLED, cumQty: "50", leavesQty: "200", avgPx: "101", lastShares: "50", lastPx: "101"},
+		{clOrdID: cxlClOrdID, origClOrdID: order.ClOrdID, execType: enum.ExecType_CANCELED, ordStatus: enum.OrdStatus_CANCELED, cumQty: "50", leavesQty: "0", avgPx: "101"},
+	} {
+		if err := recorder.FromApp(r.message(t), testSessionID); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	replayed := &FIXApplication{SessionIDs: make(map[string]quickfix.SessionID), OrderManager: oms.NewOrderManager(new(ClOrdIDGenerator))}
+	if err := Replay(context.Background(), &recording, replayed, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	want, got := app.GetAll(), replayed.GetAll()
+	if len(got) != len(want) {
+		t.Fatalf("got %v orders, want %v", len(got), len(want))
+	}
+	for i := range want {
+		w, g := *want[i], *got[i]
+		if g.ID != w.ID || g.ClOrdID != w.ClOrdID || g.OrdStatus != w.OrdStatus || g.Closed != w.Closed || g.Open != w.Open ||
+			g.AvgPx != w.AvgPx || !g.CreatedAt.Equal(w.CreatedAt) || g.SessionID != w.SessionID {
+			t.Errorf("order %v: got %+v, want %+v", i, g, w)
+		}
+	}
+
+	if got, want := len(replayed.GetAllExecutions()), len(app.GetAllExecutions()); got != want {
+		t.Errorf("got %v executions, want %v", got, want)
+	}
+}
+
+func TestRecordReplayParent(t *testing.T) {
+	app, parent := newTestApplication(t)
+	var recording bytes.Buffer
+	recorder, err := NewRecorder(app, &recording)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	child := testOrder(t, testSessionID.BeginString, enum.OrdType_LIMIT)
+	child.Session, child.ParentID = testSessionID.String(), parent.ID
+	_ = app.Save(&child)
+
+	r := report{clOrdID: child.ClOrdID, execType: enum.ExecType_NEW, ordStatus: enum.OrdStatus_NEW, cumQty: "0", leavesQty: "250", avgPx: "0"}
+	if err := recorder.FromApp(r.message(t), testSessionID); err != nil {
+		t.Fatal(err)
+	}
+
+	replayed := &FIXApplication{SessionIDs: make(map[string]quickfix.SessionID), OrderManager: oms.NewOrderManager(new(ClOrdIDGenerator))}
+	if err := Replay(context.Background(), &recording, replayed, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	restored, err := replayed.Get(parent.ID)
+	if err != nil {
+		t.Fatalf("parent not restored: %v", err)
+	}
+	if restored.ClOrdID != parent.ClOrdID {
+		t.Errorf("got parent clordid %v, want %v", restored.ClOrdID, parent.ClOrdID)
+	}
+	if got, err := replayed.Get(child.ID); err != nil || got.ParentID != parent.ID || got.OrdStatus != enum.OrdStatus_NEW {
+		t.Errorf("got child %+v, %v, want it under the parent", got, err)
+	}
+}
+
+func TestParseSessionID(t *testing.T) {
+	for _, sessionID := range []quickfix.SessionID{
+		testSessionID,
+		{BeginString: "FIXT.1.1", SenderCompID: "A", SenderSubID: "S", SenderLocationID: "L", TargetCompID: "B", TargetSubID: "T", Qualifier: "q"},
+	} {
+		got, err := ParseSessionID(sessionID.String())
+		if err != nil || got != sessionID {
+			t.Errorf("ParseSessionID(%v) = %+v, %v", sessionID, got, err)
+		}
+	}
+
+	if _, err := ParseSessionID("FIX.4.2"); err == nil {
+		t.Error("expected an error")
+	}
+}
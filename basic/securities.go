@@ -0,0 +1,151 @@
+package basic
+
+import (
+	"github.com/quickfixgo/enum"
+	"github.com/quickfixgo/tag"
+	"github.com/quickfixgo/traderui/secmaster"
+	"github.com/shopspring/decimal"
+
+	fix43sl "github.com/quickfixgo/fix43/securitylist"
+	fix44sl "github.com/quickfixgo/fix44/securitylist"
+	fix50sl "github.com/quickfixgo/fix50/securitylist"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+// fieldGetter is a message body or repeating group
+type fieldGetter interface {
+	Has(quickfix.Tag) bool
+	GetString(quickfix.Tag) (string, quickfix.MessageRejectError)
+}
+
+func (a *FIXApplication) onSecurityDefinition(msg *quickfix.Message, sessionID quickfix.SessionID) quickfix.MessageRejectError {
+	if a.Securities == nil {
+		return quickfix.UnsupportedMessageType()
+	}
+
+	a.Securities.Lock()
+	defer a.Securities.Unlock()
+
+	return a.updateSecurity(&msg.Body)
+}
+
+func (a *FIXApplication) onSecurityList(msg *quickfix.Message, sessionID quickfix.SessionID) quickfix.MessageRejectError {
+	if a.Securities == nil {
+		return quickfix.UnsupportedMessageType()
+	}
+
+	if !msg.Body.Has(tag.NoRelatedSym) {
+		return nil
+	}
+
+	relatedSyms, err := getRelatedSyms(msg)
+	if err != nil {
+		return err
+	}
+
+	a.Securities.Lock()
+	defer a.Securities.Unlock()
+
+	for i := 0; i < relatedSyms.Len(); i++ {
+		if err := a.updateSecurity(relatedSyms.Get(i)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getRelatedSyms reads the NoRelatedSym group of a SecurityList with the template of its FIX
+// version, which covers the nested groups of the instruments
+func getRelatedSyms(msg *quickfix.Message) (*quickfix.RepeatingGroup, quickfix.MessageRejectError) {
+	beginString, err := msg.Header.GetString(tag.BeginString)
+	if err != nil {
+		return nil, err
+	}
+
+	switch beginString {
+	case quickfix.BeginStringFIX43:
+		grp, err := fix43sl.FromMessage(msg).GetNoRelatedSym()
+		return grp.RepeatingGroup, err
+	case quickfix.BeginStringFIX44:
+		grp, err := fix44sl.FromMessage(msg).GetNoRelatedSym()
+		return grp.RepeatingGroup, err
+	case quickfix.BeginStringFIXT11:
+		grp, err := fix50sl.FromMessage(msg).GetNoRelatedSym()
+		return grp.RepeatingGroup, err
+	}
+
+	return nil, quickfix.UnsupportedMessageType()
+}
+
+// updateSecurity merges the instrument fields present in f into the security master
+func (a *FIXApplication) updateSecurity(f fieldGetter) quickfix.MessageRejectError {
+	symbol, err := f.GetString(tag.Symbol)
+	if err != nil {
+		return err
+	}
+
+	sec := secmaster.Security{Symbol: symbol}
+	if existing, ok := a.Securities.Get(symbol); ok {
+		sec = *existing
+	}
+
+	if f.Has(tag.SecurityType) {
+		securityType, _ := f.GetString(tag.SecurityType)
+		sec.SecurityType = enum.SecurityType(securityType)
+	}
+	if f.Has(tag.Currency) {
+		sec.Currency, _ = f.GetString(tag.Currency)
+	}
+	if f.Has(tag.MaturityMonthYear) {
+		sec.MaturityMonthYear, _ = f.GetString(tag.MaturityMonthYear)
+	}
+	if sec.TickSize, err = getDecimal(f, tag.MinPriceIncrement, sec.TickSize); err != nil {
+		return err
+	}
+	if sec.LotSize, err = getDecimal(f, tag.RoundLot, sec.LotSize); err != nil {
+		return err
+	}
+	if sec.Multiplier, err = getDecimal(f, tag.ContractMultiplier, sec.Multiplier); err != nil {
+		return err
+	}
+	if f.Has(tag.SecurityTradingStatus) {
+		status, _ := f.GetString(tag.SecurityTradingStatus)
+		sec.Status = tradingStatus(enum.SecurityTradingStatus(status), sec.Status)
+	}
+
+	a.Securities.Put(sec)
+	return nil
+}
+
+// getDecimal returns the decimal value of t, or d if f does not have it
+func getDecimal(f fieldGetter, t quickfix.Tag, d decimal.Decimal) (decimal.Decimal, quickfix.MessageRejectError) {
+	if !f.Has(t) {
+		return d, nil
+	}
+
+	s, err := f.GetString(t)
+	if err != nil {
+		return d, err
+	}
+
+	v, parseErr := decimal.NewFromString(s)
+	if parseErr != nil {
+		return d, quickfix.IncorrectDataFormatForValue(t)
+	}
+
+	return v, nil
+}
+
+// tradingStatus maps a SecurityTradingStatus to halted or trading, keeping status for the others
+func tradingStatus(status enum.SecurityTradingStatus, current secmaster.Status) secmaster.Status {
+	switch status {
+	case enum.SecurityTradingStatus_TRADING_HALT:
+		return secmaster.Halted
+	case enum.SecurityTradingStatus_READY_TO_TRADE, enum.SecurityTradingStatus_RESUME:
+		return secmaster.Trading
+	}
+
+	return current
+}
@@ -0,0 +1,77 @@
+package basic
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/quickfixgo/enum"
+	"github.com/quickfixgo/quickfix"
+
+	"github.com/quickfixgo/traderui/secmaster"
+)
+
+// rawMessage parses a FIX 4.4 message of msgType from the counterparty with the |-separated body
+func rawMessage(t *testing.T, msgType enum.MsgType, body string) *quickfix.Message {
+	t.Helper()
+
+	fields := fmt.Sprintf("35=%v|49=ISLD|56=TW|34=2|52=20261019-12:00:00.000|%v|", msgType, body)
+	fields = strings.ReplaceAll(fields, "|", "\x01")
+	raw := fmt.Sprintf("8=FIX.4.4\x019=%v\x01%v", len(fields), fields)
+
+	var sum int
+	for i := 0; i < len(raw); i++ {
+		sum += int(raw[i])
+	}
+	raw += fmt.Sprintf("10=%03d\x01", sum%256)
+
+	msg := quickfix.NewMessage()
+	if err := quickfix.ParseMessage(msg, bytes.NewBufferString(raw)); err != nil {
+		t.Fatal(err)
+	}
+
+	return msg
+}
+
+func TestSecurityReferenceData(t *testing.T) {
+	app, _ := newTestApplication(t)
+	if err := app.FromApp(rawMessage(t, enum.MsgType_SECURITY_DEFINITION, "320=1|322=1|323=1|55=ESZ6|167=FUT|200=202612|15=USD"), testSessionID); err == nil {
+		t.Error("expected security definitions to be unsupported without a security master")
+	}
+
+	app.Securities = secmaster.NewStore()
+	app.Securities.Put(secmaster.Security{Symbol: "IBM", Currency: "USD"})
+
+	definition := rawMessage(t, enum.MsgType_SECURITY_DEFINITION, "320=1|322=1|323=1|55=ESZ6|167=FUT|200=202612|15=USD|231=50|969=0.25")
+	if err := app.FromApp(definition, testSessionID); err != nil {
+		t.Fatal(err)
+	}
+
+	list := rawMessage(t, enum.MsgType_SECURITY_LIST, "320=2|322=2|560=0|146=2|55=IBM|454=1|455=US4592001014|456=4|167=CS|561=100|55=XYZ|711=1|311=IBM|167=CS|561=10")
+	if err := app.FromApp(list, testSessionID); err != nil {
+		t.Fatal(err)
+	}
+
+	es, ok := app.Securities.Get("ESZ6")
+	if !ok || es.SecurityType != enum.SecurityType_FUTURE || es.MaturityMonthYear != "202612" || es.Multiplier.String() != "50" || es.TickSize.String() != "0.25" {
+		t.Errorf("got ESZ6 %+v", es)
+	}
+
+	ibm, _ := app.Securities.Get("IBM")
+	if ibm.Currency != "USD" || ibm.SecurityType != enum.SecurityType_COMMON_STOCK || ibm.LotSize.String() != "100" {
+		t.Errorf("got IBM %+v, want the list merged into the loaded security", ibm)
+	}
+
+	if xyz, ok := app.Securities.Get("XYZ"); !ok || xyz.SecurityType != enum.SecurityType_COMMON_STOCK || xyz.LotSize.String() != "10" {
+		t.Errorf("got XYZ %+v, want the fields after its underlyings read", xyz)
+	}
+
+	halt := rawMessage(t, enum.MsgType_SECURITY_DEFINITION, "320=2|322=3|323=1|55=XYZ|326=2")
+	if err := app.FromApp(halt, testSessionID); err != nil {
+		t.Fatal(err)
+	}
+	if xyz, _ := app.Securities.Get("XYZ"); xyz.Status != secmaster.Halted {
+		t.Errorf("got XYZ %+v, want it halted", xyz)
+	}
+}
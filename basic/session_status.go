@@ -0,0 +1,82 @@
+package basic
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/quickfixgo/enum"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+// SessionStatus is the logon state of a session
+type SessionStatus struct {
+	Session       string     `json:"session_id"`
+	LoggedOn      bool       `json:"logged_on"`
+	LastLogon     *time.Time `json:"last_logon"`
+	LastLogout    *time.Time `json:"last_logout"`
+	LastHeartbeat *time.Time `json:"last_heartbeat"`
+}
+
+// sessionStatuses tracks the status of each session, the zero value is ready to use
+type sessionStatuses struct {
+	sync.Mutex
+	statuses map[quickfix.SessionID]*SessionStatus
+}
+
+func (s *sessionStatuses) update(sessionID quickfix.SessionID, update func(status *SessionStatus, now time.Time)) {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.statuses == nil {
+		s.statuses = make(map[quickfix.SessionID]*SessionStatus)
+	}
+
+	status, ok := s.statuses[sessionID]
+	if !ok {
+		status = &SessionStatus{Session: sessionID.String()}
+		s.statuses[sessionID] = status
+	}
+
+	update(status, time.Now())
+}
+
+func (s *sessionStatuses) created(sessionID quickfix.SessionID) {
+	s.update(sessionID, func(*SessionStatus, time.Time) {})
+}
+
+func (s *sessionStatuses) loggedOn(sessionID quickfix.SessionID, loggedOn bool) {
+	s.update(sessionID, func(status *SessionStatus, now time.Time) {
+		status.LoggedOn = loggedOn
+		if loggedOn {
+			status.LastLogon = &now
+		} else {
+			status.LastLogout = &now
+		}
+	})
+}
+
+func (s *sessionStatuses) received(sessionID quickfix.SessionID, msgType string) {
+	if enum.MsgType(msgType) != enum.MsgType_HEARTBEAT {
+		return
+	}
+
+	s.update(sessionID, func(status *SessionStatus, now time.Time) {
+		status.LastHeartbeat = &now
+	})
+}
+
+// SessionStatuses returns the status of every session ordered by session id
+func (a *FIXApplication) SessionStatuses() []SessionStatus {
+	a.status.Lock()
+	defer a.status.Unlock()
+
+	statuses := make([]SessionStatus, 0, len(a.status.statuses))
+	for _, status := range a.status.statuses {
+		statuses = append(statuses, *status)
+	}
+
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Session < statuses[j].Session })
+	return statuses
+}
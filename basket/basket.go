@@ -0,0 +1,113 @@
+package basket
+
+import (
+	"github.com/quickfixgo/enum"
+	"github.com/shopspring/decimal"
+
+	"github.com/quickfixgo/traderui/oms"
+)
+
+// Mode is how a confirmed basket is sent to the counterparty
+type Mode string
+
+// Send modes
+const (
+	// Orders sends one NewOrderSingle per row
+	Orders Mode = "orders"
+	// List sends all rows in a single NewOrderList
+	List Mode = "list"
+)
+
+// State is the lifecycle state of a basket
+type State string
+
+// Basket states
+const (
+	Preview   State = "preview"
+	Sent      State = "sent"
+	Cancelled State = "cancelled"
+)
+
+// Row is one order of a basket along with its validation result
+type Row struct {
+	Row   int        `json:"row"`
+	Order *oms.Order `json:"order"`
+	Error string     `json:"error"`
+}
+
+// Basket is a list of orders tracked as a unit
+type Basket struct {
+	ID       int    `json:"id"`
+	ListID   string `json:"list_id"`
+	User     string `json:"user"`
+	Mode     Mode   `json:"mode"`
+	State    State  `json:"state"`
+	Rows     []*Row `json:"rows"`
+	Quantity string `json:"quantity"`
+	Closed   string `json:"closed"`
+	Open     string `json:"open"`
+}
+
+// Valid is true if every row passed validation
+func (b *Basket) Valid() bool {
+	for _, row := range b.Rows {
+		if row.Error != "" {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Orders returns the orders of the basket
+func (b *Basket) Orders() []*oms.Order {
+	orders := make([]*oms.Order, 0, len(b.Rows))
+	for _, row := range b.Rows {
+		orders = append(orders, row.Order)
+	}
+
+	return orders
+}
+
+// Working returns the sent orders that are still live at the counterparty
+func (b *Basket) Working() []*oms.Order {
+	var working []*oms.Order
+	if b.State != Sent {
+		return working
+	}
+
+	for _, row := range b.Rows {
+		if row.Order.Open != "0" && row.Order.OrdStatus != enum.OrdStatus_REJECTED {
+			working = append(working, row.Order)
+		}
+	}
+
+	return working
+}
+
+// Update aggregates the fill progress of the basket orders
+func (b *Basket) Update() {
+	qty, closed, open := decimal.Zero, decimal.Zero, decimal.Zero
+	for _, row := range b.Rows {
+		qty = qty.Add(row.Order.QuantityDecimal)
+		if b.State == Preview {
+			continue
+		}
+
+		cumQty, _ := decimal.NewFromString(row.Order.Closed)
+		closed = closed.Add(cumQty)
+
+		switch {
+		case row.Order.OrdStatus == enum.OrdStatus_REJECTED:
+		case row.Order.Open == "":
+			open = open.Add(row.Order.QuantityDecimal)
+		default:
+			leavesQty, _ := decimal.NewFromString(row.Order.Open)
+			open = open.Add(leavesQty)
+		}
+	}
+
+	b.Quantity = qty.String()
+	b.Closed = closed.String()
+	b.Open = open.String()
+}
@@ -0,0 +1,87 @@
+package basket
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/quickfixgo/enum"
+
+	"github.com/quickfixgo/traderui/oms"
+)
+
+// ParseCSV reads orders from CSV. The first record is a header naming the columns with the
+// json names of the order fields, e.g. symbol,side,quantity,ord_type,price,account,session_id
+func ParseCSV(r io.Reader) ([]oms.Order, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("invalid csv header: %v", err)
+	}
+
+	var orders []oms.Order
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var order oms.Order
+		for i, value := range record {
+			if err := setColumn(&order, strings.TrimSpace(header[i]), strings.TrimSpace(value)); err != nil {
+				return nil, fmt.Errorf("line %v: %v", line, err)
+			}
+		}
+		orders = append(orders, order)
+	}
+
+	return orders, nil
+}
+
+func setColumn(order *oms.Order, column, value string) (err error) {
+	switch column {
+	case "symbol":
+		order.Symbol = value
+	case "quantity":
+		order.Quantity = value
+	case "account":
+		order.Account = value
+	case "session_id":
+		order.Session = value
+	case "side":
+		order.Side = enum.Side(value)
+	case "ord_type":
+		order.OrdType = enum.OrdType(value)
+	case "price":
+		order.Price = value
+	case "stop_price":
+		order.StopPrice = value
+	case "security_type":
+		order.SecurityType = enum.SecurityType(value)
+	case "security_desc":
+		order.SecurityDesc = value
+	case "maturity_month_year":
+		order.MaturityMonthYear = value
+	case "maturity_day":
+		if value != "" {
+			if order.MaturityDay, err = strconv.Atoi(value); err != nil {
+				return fmt.Errorf("invalid maturity_day %v", value)
+			}
+		}
+	case "put_or_call":
+		order.PutOrCall = enum.PutOrCall(value)
+	case "strike_price":
+		order.StrikePrice = value
+	default:
+		return fmt.Errorf("unknown column %v", column)
+	}
+
+	return nil
+}
@@ -0,0 +1,103 @@
+package basket
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/quickfixgo/traderui/oms"
+)
+
+// Manager tracks baskets
+type Manager struct {
+	sync.RWMutex
+	basketID int
+	baskets  map[int]*Basket
+}
+
+// NewManager returns an empty basket manager
+func NewManager() *Manager {
+	return &Manager{
+		baskets: make(map[int]*Basket),
+	}
+}
+
+// New saves a preview basket for orders. validate is called for each order and failures are
+// recorded on the row.
+func (m *Manager) New(mode Mode, orders []oms.Order, validate func(*oms.Order) error) (*Basket, error) {
+	switch mode {
+	case "":
+		mode = Orders
+	case Orders, List:
+	default:
+		return nil, fmt.Errorf("unknown basket mode %v", mode)
+	}
+
+	if len(orders) == 0 {
+		return nil, fmt.Errorf("basket is empty")
+	}
+
+	m.basketID++
+	b := &Basket{
+		ID:     m.basketID,
+		ListID: fmt.Sprintf("BASKET-%v", m.basketID),
+		Mode:   mode,
+		State:  Preview,
+		Rows:   make([]*Row, 0, len(orders)),
+	}
+
+	for i := range orders {
+		row := &Row{Row: i + 1, Order: &orders[i]}
+		if err := validate(row.Order); err != nil {
+			row.Error = err.Error()
+		}
+		b.Rows = append(b.Rows, row)
+	}
+
+	if mode == List && b.Valid() {
+		for _, row := range b.Rows[1:] {
+			if row.Order.SessionID != b.Rows[0].Order.SessionID {
+				row.Error = "list baskets must be sent on a single session"
+			}
+		}
+	}
+
+	b.Update()
+	m.baskets[b.ID] = b
+
+	return b, nil
+}
+
+// Get returns the basket with id
+func (m *Manager) Get(id int) (*Basket, error) {
+	b, ok := m.baskets[id]
+	if !ok {
+		return nil, fmt.Errorf("could not find basket with id %v", id)
+	}
+
+	return b, nil
+}
+
+// GetAll returns all baskets
+func (m *Manager) GetAll() []*Basket {
+	baskets := make([]*Basket, 0, len(m.baskets))
+	for _, v := range m.baskets {
+		baskets = append(baskets, v)
+	}
+
+	return baskets
+}
+
+// Discard removes a basket that was never sent
+func (m *Manager) Discard(id int) error {
+	b, err := m.Get(id)
+	if err != nil {
+		return err
+	}
+
+	if b.State != Preview {
+		return fmt.Errorf("cannot discard %v basket", b.State)
+	}
+
+	delete(m.baskets, id)
+	return nil
+}
@@ -0,0 +1,285 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/traderui/audit"
+	"github.com/quickfixgo/traderui/auth"
+	"github.com/quickfixgo/traderui/basket"
+	"github.com/quickfixgo/traderui/metrics"
+	"github.com/quickfixgo/traderui/oms"
+)
+
+func (c tradeClient) BasketsAsJSON(r *http.Request) (string, error) {
+	c.baskets.Lock()
+	defer c.baskets.Unlock()
+	c.RLock()
+	defer c.RUnlock()
+
+	baskets := make([]*basket.Basket, 0)
+	for _, b := range c.baskets.GetAll() {
+		if canViewBasket(r, b) {
+			b.Update()
+			baskets = append(baskets, b)
+		}
+	}
+
+	b, err := json.Marshal(baskets)
+	return string(b), err
+}
+
+// canViewBasket is true if the user of the request may see every order of the basket
+func canViewBasket(r *http.Request, b *basket.Basket) bool {
+	for _, row := range b.Rows {
+		if !auth.CanView(r, row.Order.Session, row.Order.Account) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// authorizeBasket checks the user of the request may perform action on every order of the basket,
+// writing an error if not
+func authorizeBasket(w http.ResponseWriter, r *http.Request, b *basket.Basket, action auth.Action) bool {
+	for _, row := range b.Rows {
+		if !authorize(w, r, action, row.Order.Session, row.Order.Account) {
+			return false
+		}
+	}
+
+	if !auth.CanModify(r, b.User) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return false
+	}
+
+	return true
+}
+
+func (c tradeClient) fetchRequestedBasketID(r *http.Request) int {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		panic(err)
+	}
+
+	return id
+}
+
+func (c tradeClient) writeBasketJSON(w http.ResponseWriter, b *basket.Basket) {
+	c.RLock()
+	defer c.RUnlock()
+
+	b.Update()
+	outgoingJSON, err := json.Marshal(b)
+	if err != nil {
+		log.Printf("[ERROR] err = %+v\n", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprint(w, string(outgoingJSON))
+}
+
+func (c tradeClient) getBaskets(w http.ResponseWriter, r *http.Request) {
+	outgoingJSON, err := c.BasketsAsJSON(r)
+	if err != nil {
+		log.Printf("[ERROR] err = %+v\n", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprint(w, outgoingJSON)
+}
+
+func (c tradeClient) getBasket(w http.ResponseWriter, r *http.Request) {
+	c.baskets.Lock()
+	defer c.baskets.Unlock()
+
+	b, err := c.baskets.Get(c.fetchRequestedBasketID(r))
+	if err != nil || !canViewBasket(r, b) {
+		http.Error(w, "Basket not found", http.StatusNotFound)
+		return
+	}
+
+	c.writeBasketJSON(w, b)
+}
+
+// newBasket validates the uploaded orders and returns a preview. Orders are read from a csv
+// body when the Content-Type is text/csv, otherwise from a json array.
+func (c tradeClient) newBasket(w http.ResponseWriter, r *http.Request) {
+	var orders []oms.Order
+	var err error
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "text/csv" {
+		orders, err = basket.ParseCSV(r.Body)
+	} else {
+		err = json.NewDecoder(r.Body).Decode(&orders)
+	}
+
+	if err != nil {
+		log.Printf("[ERROR] %v\n", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	user := auth.Username(r)
+	for i := range orders {
+		orders[i].User = user
+	}
+
+	c.baskets.Lock()
+	defer c.baskets.Unlock()
+
+	mode := basket.Mode(r.URL.Query().Get("mode"))
+	validate := func(order *oms.Order) error {
+		if err := auth.Authorize(r, auth.ActionNew, order.Session, order.Account); err != nil {
+			return err
+		}
+
+		if err := c.validateOrder(order); err != nil {
+			return err
+		}
+
+		if mode != basket.List {
+			return nil
+		}
+
+		if err := c.validateListOrder(order); err != nil {
+			c.metrics.OrderRejected(order.Session, metrics.RejectedValidation)
+			return err
+		}
+
+		return nil
+	}
+
+	b, err := c.baskets.New(mode, orders, validate)
+	if err != nil {
+		log.Printf("[ERROR] %v\n", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	b.User = user
+
+	c.writeBasketJSON(w, b)
+}
+
+// validateListOrder checks that the validated order can be sent in a NewOrderList, which only
+// FIX.4.2 and later define and which carries neither legs nor quote ids
+func (c tradeClient) validateListOrder(order *oms.Order) error {
+	switch order.SessionID.BeginString {
+	case quickfix.BeginStringFIX42, quickfix.BeginStringFIX43, quickfix.BeginStringFIX44, quickfix.BeginStringFIXT11:
+	default:
+		return fmt.Errorf("Cannot send order lists to a %v session", order.SessionID.BeginString)
+	}
+
+	switch {
+	case c.PaperSessions[order.SessionID]:
+		return errors.New("Cannot send order lists to a paper session")
+	case len(order.Legs) > 0:
+		return errors.New("Cannot send multileg orders in an order list")
+	case order.QuoteID != "":
+		return errors.New("Cannot send quoted orders in an order list")
+	}
+
+	return nil
+}
+
+func (c tradeClient) confirmBasket(w http.ResponseWriter, r *http.Request) {
+	c.baskets.Lock()
+	defer c.baskets.Unlock()
+
+	b, err := c.baskets.Get(c.fetchRequestedBasketID(r))
+	if err != nil || !canViewBasket(r, b) {
+		http.Error(w, "Basket not found", http.StatusNotFound)
+		return
+	}
+
+	if !authorizeBasket(w, r, b, auth.ActionNew) {
+		return
+	}
+
+	if b.State != basket.Preview {
+		http.Error(w, fmt.Sprintf("cannot confirm %v basket", b.State), http.StatusConflict)
+		return
+	}
+
+	if !b.Valid() {
+		http.Error(w, "basket has invalid rows", http.StatusBadRequest)
+		return
+	}
+
+	b.State = basket.Sent
+	switch b.Mode {
+	case basket.List:
+		if err = c.SendOrderList(b.ListID, b.Orders()); err != nil {
+			log.Printf("[ERROR] %v\n", err)
+			for _, row := range b.Rows {
+				row.Error = err.Error()
+			}
+		}
+
+	default:
+		for _, row := range b.Rows {
+			if err := c.SendOrder(row.Order); err != nil {
+				log.Printf("[ERROR] %v\n", err)
+				row.Error = err.Error()
+			}
+		}
+	}
+
+	for _, order := range b.Orders() {
+		audit.AddClOrdIDs(r, order.ClOrdID)
+	}
+
+	c.writeBasketJSON(w, b)
+}
+
+// deleteBasket discards a basket still in preview, or cancels the working orders of a sent basket
+func (c tradeClient) deleteBasket(w http.ResponseWriter, r *http.Request) {
+	c.baskets.Lock()
+	defer c.baskets.Unlock()
+
+	id := c.fetchRequestedBasketID(r)
+	b, err := c.baskets.Get(id)
+	if err != nil || !canViewBasket(r, b) {
+		http.Error(w, "Basket not found", http.StatusNotFound)
+		return
+	}
+
+	if !authorizeBasket(w, r, b, auth.ActionCancel) {
+		return
+	}
+
+	if b.State == basket.Preview {
+		if err = c.baskets.Discard(id); err != nil {
+			http.Error(w, err.Error(), http.StatusConflict)
+		}
+		return
+	}
+
+	c.RLock()
+	working := b.Working()
+	c.RUnlock()
+
+	b.State = basket.Cancelled
+	for _, order := range working {
+		audit.AddClOrdIDs(r, order.ClOrdID)
+		if err := c.CancelOrder(order); err != nil {
+			log.Printf("[ERROR] basket %v: cancel of order %v failed: %v\n", b.ID, order.ID, err)
+		}
+	}
+
+	c.writeBasketJSON(w, b)
+}
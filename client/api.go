@@ -0,0 +1,325 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/quickfixgo/enum"
+
+	"github.com/quickfixgo/traderui/algo"
+	"github.com/quickfixgo/traderui/audit"
+	"github.com/quickfixgo/traderui/basic"
+	"github.com/quickfixgo/traderui/basket"
+	"github.com/quickfixgo/traderui/oms"
+	"github.com/quickfixgo/traderui/rfq"
+	"github.com/quickfixgo/traderui/secmaster"
+	"github.com/quickfixgo/traderui/sender"
+)
+
+// Orders returns a page of orders and the cursor of the next page, 0 on the last page
+func (c *Client) Orders(ctx context.Context, f OrderFilter) ([]*oms.Order, int, error) {
+	var orders []*oms.Order
+	header, err := c.do(ctx, http.MethodGet, "/orders", f.values(), nil, &orders)
+	return orders, nextCursor(header), err
+}
+
+// Order returns the order with id
+func (c *Client) Order(ctx context.Context, id int) (*oms.Order, error) {
+	order := new(oms.Order)
+	_, err := c.do(ctx, http.MethodGet, fmt.Sprintf("/orders/%v", id), nil, nil, order)
+	return order, err
+}
+
+// NewOrder sends an order and returns it as saved
+func (c *Client) NewOrder(ctx context.Context, order oms.Order) (*oms.Order, error) {
+	sent := new(oms.Order)
+	_, err := c.do(ctx, http.MethodPost, "/orders", nil, order, sent)
+	return sent, err
+}
+
+// CancelOrder requests cancellation of the order with id
+func (c *Client) CancelOrder(ctx context.Context, id int) (*oms.Order, error) {
+	order := new(oms.Order)
+	_, err := c.do(ctx, http.MethodDelete, fmt.Sprintf("/orders/%v", id), nil, nil, order)
+	return order, err
+}
+
+// OrderAmendment changes the quantity and prices of an order, empty fields are left unchanged
+type OrderAmendment struct {
+	Quantity  string `json:"quantity"`
+	Price     string `json:"price"`
+	StopPrice string `json:"stop_price"`
+}
+
+// AmendOrder requests a cancel/replace of the order with id
+func (c *Client) AmendOrder(ctx context.Context, id int, amendment OrderAmendment) (*oms.Order, error) {
+	order := new(oms.Order)
+	_, err := c.do(ctx, http.MethodPatch, fmt.Sprintf("/orders/%v", id), nil, amendment, order)
+	return order, err
+}
+
+// Templates returns the order templates of the user
+func (c *Client) Templates(ctx context.Context) ([]*oms.Template, error) {
+	var templates []*oms.Template
+	_, err := c.do(ctx, http.MethodGet, "/templates", nil, nil, &templates)
+	return templates, err
+}
+
+// Template returns the order template with id
+func (c *Client) Template(ctx context.Context, id int) (*oms.Template, error) {
+	t := new(oms.Template)
+	_, err := c.do(ctx, http.MethodGet, fmt.Sprintf("/templates/%v", id), nil, nil, t)
+	return t, err
+}
+
+// NewTemplate saves an order template
+func (c *Client) NewTemplate(ctx context.Context, name string, order oms.Order) (*oms.Template, error) {
+	t := new(oms.Template)
+	_, err := c.do(ctx, http.MethodPost, "/templates", nil, oms.Template{Name: name, Order: order}, t)
+	return t, err
+}
+
+// UpdateTemplate replaces the name and order of the template with id
+func (c *Client) UpdateTemplate(ctx context.Context, id int, name string, order oms.Order) (*oms.Template, error) {
+	t := new(oms.Template)
+	_, err := c.do(ctx, http.MethodPut, fmt.Sprintf("/templates/%v", id), nil, oms.Template{Name: name, Order: order}, t)
+	return t, err
+}
+
+// DeleteTemplate deletes the template with id
+func (c *Client) DeleteTemplate(ctx context.Context, id int) error {
+	_, err := c.do(ctx, http.MethodDelete, fmt.Sprintf("/templates/%v", id), nil, nil, nil)
+	return err
+}
+
+// TemplateOverrides change the order of a template on submit, empty fields are taken from the
+// template
+type TemplateOverrides struct {
+	Symbol    string `json:"symbol,omitempty"`
+	Quantity  string `json:"quantity,omitempty"`
+	Account   string `json:"account,omitempty"`
+	Session   string `json:"session_id,omitempty"`
+	Side      string `json:"side,omitempty"`
+	OrdType   string `json:"ord_type,omitempty"`
+	Price     string `json:"price,omitempty"`
+	StopPrice string `json:"stop_price,omitempty"`
+}
+
+// SubmitTemplate sends an order from the template with id and returns it as saved
+func (c *Client) SubmitTemplate(ctx context.Context, id int, overrides TemplateOverrides) (*oms.Order, error) {
+	order := new(oms.Order)
+	_, err := c.do(ctx, http.MethodPost, fmt.Sprintf("/templates/%v/submit", id), nil, overrides, order)
+	return order, err
+}
+
+// Executions returns a page of executions and the cursor of the next page, 0 on the last page
+func (c *Client) Executions(ctx context.Context, f ExecutionFilter) ([]*oms.Execution, int, error) {
+	var executions []*oms.Execution
+	header, err := c.do(ctx, http.MethodGet, "/executions", f.values(), nil, &executions)
+	return executions, nextCursor(header), err
+}
+
+// Execution returns the execution with id
+func (c *Client) Execution(ctx context.Context, id int) (*oms.Execution, error) {
+	exec := new(oms.Execution)
+	_, err := c.do(ctx, http.MethodGet, fmt.Sprintf("/executions/%v", id), nil, nil, exec)
+	return exec, err
+}
+
+// Securities returns the security master in symbol order
+func (c *Client) Securities(ctx context.Context) ([]*secmaster.Security, error) {
+	var securities []*secmaster.Security
+	_, err := c.do(ctx, http.MethodGet, "/securities", nil, nil, &securities)
+	return securities, err
+}
+
+// Security returns the reference data of symbol
+func (c *Client) Security(ctx context.Context, symbol string) (*secmaster.Security, error) {
+	sec := new(secmaster.Security)
+	_, err := c.do(ctx, http.MethodGet, "/securities/"+url.PathEscape(symbol), nil, nil, sec)
+	return sec, err
+}
+
+// SecurityDefinitionRequest sends a SecurityDefinitionRequest
+func (c *Client) SecurityDefinitionRequest(ctx context.Context, req secmaster.SecurityDefinitionRequest) error {
+	_, err := c.do(ctx, http.MethodPost, "/securitydefinitionrequest", nil, req, nil)
+	return err
+}
+
+// Algos returns every algo
+func (c *Client) Algos(ctx context.Context) ([]*algo.Algo, error) {
+	var algos []*algo.Algo
+	_, err := c.do(ctx, http.MethodGet, "/algos", nil, nil, &algos)
+	return algos, err
+}
+
+// Algo returns the algo with id
+func (c *Client) Algo(ctx context.Context, id int) (*algo.Algo, error) {
+	a := new(algo.Algo)
+	_, err := c.do(ctx, http.MethodGet, fmt.Sprintf("/algos/%v", id), nil, nil, a)
+	return a, err
+}
+
+// NewAlgo starts an algo working the parent order
+func (c *Client) NewAlgo(ctx context.Context, parent oms.Order, params algo.Params) (*algo.Algo, error) {
+	req := struct {
+		oms.Order
+		Algo algo.Params `json:"algo"`
+	}{parent, params}
+
+	a := new(algo.Algo)
+	_, err := c.do(ctx, http.MethodPost, "/algos", nil, req, a)
+	return a, err
+}
+
+func (c *Client) algoAction(ctx context.Context, method, path string) (*algo.Algo, error) {
+	a := new(algo.Algo)
+	_, err := c.do(ctx, method, path, nil, nil, a)
+	return a, err
+}
+
+// PauseAlgo pauses the algo with id
+func (c *Client) PauseAlgo(ctx context.Context, id int) (*algo.Algo, error) {
+	return c.algoAction(ctx, http.MethodPost, fmt.Sprintf("/algos/%v/pause", id))
+}
+
+// ResumeAlgo resumes the algo with id
+func (c *Client) ResumeAlgo(ctx context.Context, id int) (*algo.Algo, error) {
+	return c.algoAction(ctx, http.MethodPost, fmt.Sprintf("/algos/%v/resume", id))
+}
+
+// CancelAlgo cancels the algo with id and its working child orders
+func (c *Client) CancelAlgo(ctx context.Context, id int) (*algo.Algo, error) {
+	return c.algoAction(ctx, http.MethodDelete, fmt.Sprintf("/algos/%v", id))
+}
+
+// Baskets returns every basket
+func (c *Client) Baskets(ctx context.Context) ([]*basket.Basket, error) {
+	var baskets []*basket.Basket
+	_, err := c.do(ctx, http.MethodGet, "/baskets", nil, nil, &baskets)
+	return baskets, err
+}
+
+// Basket returns the basket with id
+func (c *Client) Basket(ctx context.Context, id int) (*basket.Basket, error) {
+	b := new(basket.Basket)
+	_, err := c.do(ctx, http.MethodGet, fmt.Sprintf("/baskets/%v", id), nil, nil, b)
+	return b, err
+}
+
+func modeValues(mode basket.Mode) url.Values {
+	v := url.Values{}
+	setNonEmpty(v, "mode", string(mode))
+	return v
+}
+
+// NewBasket uploads orders for preview
+func (c *Client) NewBasket(ctx context.Context, mode basket.Mode, orders []oms.Order) (*basket.Basket, error) {
+	b := new(basket.Basket)
+	_, err := c.do(ctx, http.MethodPost, "/baskets", modeValues(mode), orders, b)
+	return b, err
+}
+
+// NewBasketCSV uploads orders in csv form for preview
+func (c *Client) NewBasketCSV(ctx context.Context, mode basket.Mode, csv io.Reader) (*basket.Basket, error) {
+	b := new(basket.Basket)
+	_, err := c.do(ctx, http.MethodPost, "/baskets", modeValues(mode), rawBody{csv, "text/csv"}, b)
+	return b, err
+}
+
+// ConfirmBasket sends the orders of the basket with id
+func (c *Client) ConfirmBasket(ctx context.Context, id int) (*basket.Basket, error) {
+	b := new(basket.Basket)
+	_, err := c.do(ctx, http.MethodPost, fmt.Sprintf("/baskets/%v/confirm", id), nil, nil, b)
+	return b, err
+}
+
+// CancelBasket discards a basket preview, or cancels the working orders of a sent basket
+func (c *Client) CancelBasket(ctx context.Context, id int) (*basket.Basket, error) {
+	b := new(basket.Basket)
+	_, err := c.do(ctx, http.MethodDelete, fmt.Sprintf("/baskets/%v", id), nil, nil, b)
+	return b, err
+}
+
+// RFQs returns every quote request
+func (c *Client) RFQs(ctx context.Context) ([]*rfq.Request, error) {
+	var requests []*rfq.Request
+	_, err := c.do(ctx, http.MethodGet, "/rfqs", nil, nil, &requests)
+	return requests, err
+}
+
+// RFQ returns the quote request with id and its quotes
+func (c *Client) RFQ(ctx context.Context, id int) (*rfq.Request, error) {
+	req := new(rfq.Request)
+	_, err := c.do(ctx, http.MethodGet, fmt.Sprintf("/rfqs/%v", id), nil, nil, req)
+	return req, err
+}
+
+// NewRFQ sends a QuoteRequest
+func (c *Client) NewRFQ(ctx context.Context, req rfq.Request) (*rfq.Request, error) {
+	sent := new(rfq.Request)
+	_, err := c.do(ctx, http.MethodPost, "/rfqs", nil, req, sent)
+	return sent, err
+}
+
+// HitQuote trades on a quote, empty side and quantity default to those of the request
+func (c *Client) HitQuote(ctx context.Context, id int, quoteID string, side enum.Side, quantity, account string) (*oms.Order, error) {
+	hit := map[string]string{"side": string(side), "quantity": quantity, "account": account}
+
+	order := new(oms.Order)
+	_, err := c.do(ctx, http.MethodPost, fmt.Sprintf("/rfqs/%v/quotes/%v/hit", id, url.PathEscape(quoteID)), nil, hit, order)
+	return order, err
+}
+
+// AuditFilter selects audit records. Zero values match any record.
+type AuditFilter struct {
+	Kind    audit.Kind
+	User    string
+	Session string
+	ClOrdID string
+	Since   time.Time
+	Until   time.Time
+	Limit   int
+}
+
+// Audit queries the audit log
+func (c *Client) Audit(ctx context.Context, f AuditFilter) ([]*audit.Record, error) {
+	v := url.Values{}
+	setNonEmpty(v, "kind", string(f.Kind))
+	setNonEmpty(v, "user", f.User)
+	setNonEmpty(v, "session_id", f.Session)
+	setNonEmpty(v, "clordid", f.ClOrdID)
+	setTime(v, "since", f.Since)
+	setTime(v, "until", f.Until)
+	if f.Limit > 0 {
+		v.Set("limit", fmt.Sprint(f.Limit))
+	}
+
+	var records []*audit.Record
+	_, err := c.do(ctx, http.MethodGet, "/audit", v, nil, &records)
+	return records, err
+}
+
+// SessionHealth is the state of a session as reported by the health checks
+type SessionHealth struct {
+	basic.SessionStatus
+	Required bool                   `json:"required"`
+	Throttle *sender.ThrottleStatus `json:"throttle,omitempty"`
+}
+
+// Health is the response of the health checks
+type Health struct {
+	Status   string          `json:"status"`
+	Sessions []SessionHealth `json:"sessions"`
+}
+
+// Ready returns the readiness of the server, with an *Error of status 503 if not ready
+func (c *Client) Ready(ctx context.Context) (*Health, error) {
+	health := new(Health)
+	_, err := c.do(ctx, http.MethodGet, "/readyz", nil, nil, health)
+	return health, err
+}
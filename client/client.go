@@ -0,0 +1,221 @@
+// Package client is a typed client of the traderui REST API described by /openapi.json.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/quickfixgo/enum"
+
+	"github.com/quickfixgo/traderui/auth"
+)
+
+// NextCursorHeader carries the cursor of the next page of a list
+const NextCursorHeader = "X-Next-Cursor"
+
+// Error is a non-2xx response of the api
+type Error struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%v %v: %v", e.StatusCode, http.StatusText(e.StatusCode), e.Message)
+}
+
+// Client calls a traderui server. Requests are authenticated with Token as a bearer token when set.
+type Client struct {
+	BaseURL    string
+	Token      string
+	HTTPClient *http.Client
+}
+
+// New returns a client of the server at baseURL, e.g. http://localhost:8080
+func New(baseURL string) *Client {
+	return &Client{
+		BaseURL:    strings.TrimSuffix(baseURL, "/"),
+		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// do sends a request with a json encoded body, decoding a json response into out if not nil
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out interface{}) (http.Header, error) {
+	var reader io.Reader
+	contentType := ""
+	switch b := body.(type) {
+	case nil:
+	case rawBody:
+		reader, contentType = b.reader, b.contentType
+	default:
+		buf, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader, contentType = bytes.NewReader(buf), "application/json"
+	}
+
+	u := c.BaseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, reader)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", "application/json")
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+		return resp.Header, &Error{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(msg))}
+	}
+
+	if out != nil {
+		if err = json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return resp.Header, fmt.Errorf("decoding %v %v: %v", method, path, err)
+		}
+	}
+
+	return resp.Header, nil
+}
+
+// rawBody is a request body sent as is
+type rawBody struct {
+	reader      io.Reader
+	contentType string
+}
+
+// nextCursor reads the cursor of the next page, 0 on the last page
+func nextCursor(header http.Header) int {
+	next, _ := strconv.Atoi(header.Get(NextCursorHeader))
+	return next
+}
+
+// Login starts a session and uses its token for subsequent requests
+func (c *Client) Login(ctx context.Context, username, password string) (*auth.Session, error) {
+	var s auth.Session
+	creds := map[string]string{"username": username, "password": password}
+	if _, err := c.do(ctx, http.MethodPost, "/login", nil, creds, &s); err != nil {
+		return nil, err
+	}
+
+	c.Token = s.Token
+	return &s, nil
+}
+
+// Logout ends the session of the client
+func (c *Client) Logout(ctx context.Context) error {
+	_, err := c.do(ctx, http.MethodPost, "/logout", nil, nil, nil)
+	c.Token = ""
+	return err
+}
+
+// Sessions returns the sessions the user may trade on
+func (c *Client) Sessions(ctx context.Context) ([]string, error) {
+	var sessions []string
+	_, err := c.do(ctx, http.MethodGet, "/sessions", nil, nil, &sessions)
+	return sessions, err
+}
+
+// Page selects a page of a list. Zero values return the whole list sorted by id.
+type Page struct {
+	// Sort is a field name, prefixed with - for descending order
+	Sort   string
+	Limit  int
+	Cursor int
+}
+
+func (p Page) values(v url.Values) {
+	if p.Sort != "" {
+		v.Set("sort", p.Sort)
+	}
+	if p.Limit > 0 {
+		v.Set("limit", strconv.Itoa(p.Limit))
+	}
+	if p.Cursor > 0 {
+		v.Set("cursor", strconv.Itoa(p.Cursor))
+	}
+}
+
+func setNonEmpty(v url.Values, key, value string) {
+	if value != "" {
+		v.Set(key, value)
+	}
+}
+
+func setTime(v url.Values, key string, t time.Time) {
+	if !t.IsZero() {
+		v.Set(key, t.Format(time.RFC3339))
+	}
+}
+
+// OrderFilter selects orders. Zero values match any order.
+type OrderFilter struct {
+	Page
+	Status  []enum.OrdStatus
+	Symbol  string
+	Account string
+	Session string
+	Side    enum.Side
+	Since   time.Time
+}
+
+func (f OrderFilter) values() url.Values {
+	v := url.Values{}
+	f.Page.values(v)
+	if len(f.Status) > 0 {
+		status := make([]string, 0, len(f.Status))
+		for _, s := range f.Status {
+			status = append(status, string(s))
+		}
+		v.Set("status", strings.Join(status, ","))
+	}
+	setNonEmpty(v, "symbol", f.Symbol)
+	setNonEmpty(v, "account", f.Account)
+	setNonEmpty(v, "session_id", f.Session)
+	setNonEmpty(v, "side", string(f.Side))
+	setTime(v, "since", f.Since)
+
+	return v
+}
+
+// ExecutionFilter selects executions. Zero values match any execution.
+type ExecutionFilter struct {
+	Page
+	Symbol  string
+	Session string
+	Side    enum.Side
+	Since   time.Time
+}
+
+func (f ExecutionFilter) values() url.Values {
+	v := url.Values{}
+	f.Page.values(v)
+	setNonEmpty(v, "symbol", f.Symbol)
+	setNonEmpty(v, "session_id", f.Session)
+	setNonEmpty(v, "side", string(f.Side))
+	setTime(v, "since", f.Since)
+
+	return v
+}
@@ -0,0 +1,59 @@
+// Command auditverify checks the hash chain of a traderui audit log under its key, and that the
+// log ends at its head file, or at the head given with -head.
+//
+//	auditverify -key audit.key [-head seq:hash] audit.log
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/quickfixgo/traderui/audit"
+)
+
+func main() {
+	keyFileName := flag.String("key", "", "file of the key the records are hashed with")
+	expected := flag.String("head", "", "seq:hash the log must end at, the head file of the log if empty")
+	flag.Parse()
+	if flag.NArg() != 1 || *keyFileName == "" {
+		fmt.Fprintln(os.Stderr, "usage: auditverify -key <key file> [-head seq:hash] <audit log>")
+		os.Exit(2)
+	}
+
+	key, err := audit.LoadKey(*keyFileName)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	f, err := os.Open(flag.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	defer f.Close()
+
+	head, err := audit.Verify(f, key)
+	if err != nil {
+		fmt.Printf("FAILED after %v valid records: %v\n", head.Seq, err)
+		os.Exit(1)
+	}
+
+	want := *expected
+	if want == "" {
+		h, err := audit.ReadHead(flag.Arg(0))
+		if err != nil {
+			fmt.Printf("FAILED reading the head file: %v\n", err)
+			os.Exit(1)
+		}
+		want = h.String()
+	}
+
+	if head.String() != want {
+		fmt.Printf("FAILED: log ends at %v, want %v\n", head, want)
+		os.Exit(1)
+	}
+
+	fmt.Printf("OK: %v records, head %v\n", head.Seq, head)
+}
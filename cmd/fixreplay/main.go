@@ -0,0 +1,65 @@
+// Command fixreplay replays a traderui recording into an empty order manager and prints the
+// resulting orders and executions as json, for comparing against an expected state.
+//
+//	fixreplay [-speed 1] [-drop-copy session,...] recording.txt
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/quickfixgo/quickfix"
+
+	"github.com/quickfixgo/traderui/basic"
+	"github.com/quickfixgo/traderui/oms"
+)
+
+func main() {
+	speed := flag.Float64("speed", 0, "replay speed relative to the recording, 0 replays without waiting")
+	dropCopy := flag.String("drop-copy", "", "comma separated drop copy sessions")
+	flag.Parse()
+	if flag.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "usage: fixreplay [-speed 1] [-drop-copy session,...] <recording>")
+		os.Exit(2)
+	}
+
+	f, err := os.Open(flag.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	defer f.Close()
+
+	app := &basic.FIXApplication{
+		SessionIDs:       make(map[string]quickfix.SessionID),
+		OrderManager:     oms.NewOrderManager(new(basic.ClOrdIDGenerator)),
+		DropCopySessions: make(map[quickfix.SessionID]bool),
+	}
+	for _, session := range strings.Split(*dropCopy, ",") {
+		if session = strings.TrimSpace(session); session != "" {
+			sessionID, err := basic.ParseSessionID(session)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(2)
+			}
+			app.SessionIDs[session] = sessionID
+			app.DropCopySessions[sessionID] = true
+		}
+	}
+
+	if err := basic.Replay(context.Background(), f, app, *speed); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(struct {
+		Orders     []*oms.Order     `json:"orders"`
+		Executions []*oms.Execution `json:"executions"`
+	}{app.GetAll(), app.GetAllExecutions()})
+}
@@ -0,0 +1,342 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/quickfixgo/enum"
+
+	"github.com/quickfixgo/traderui/client"
+	"github.com/quickfixgo/traderui/oms"
+	"github.com/quickfixgo/traderui/secmaster"
+)
+
+func newFlagSet(name string) *flag.FlagSet {
+	return flag.NewFlagSet(name, flag.ContinueOnError)
+}
+
+// parseWithID parses the flags of a command taking an id, given before or after the flags
+func parseWithID(fs *flag.FlagSet, args []string) (int, error) {
+	var idArg string
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		idArg, args = args[0], args[1:]
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return 0, err
+	}
+
+	if idArg == "" {
+		idArg = fs.Arg(0)
+	}
+
+	id, err := strconv.Atoi(idArg)
+	if err != nil {
+		return 0, fmt.Errorf("%v: invalid id %q", fs.Name(), idArg)
+	}
+
+	return id, nil
+}
+
+// parseSince accepts a RFC 3339 time or a duration before now, e.g. 15m
+func parseSince(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+
+	if d, err := time.ParseDuration(s); err == nil {
+		return time.Now().Add(-d), nil
+	}
+
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid since %v", s)
+	}
+
+	return t, nil
+}
+
+// pageFlags adds the paging flags to fs
+func pageFlags(fs *flag.FlagSet, page *client.Page) {
+	fs.StringVar(&page.Sort, "sort", "", "field to sort by, prefixed with - for descending order")
+	fs.IntVar(&page.Limit, "limit", 0, "maximum number of results")
+	fs.IntVar(&page.Cursor, "cursor", 0, "cursor of the page to return")
+}
+
+// orderFilterFlags adds the order filter flags to fs, returning the filter once fs is parsed
+func orderFilterFlags(fs *flag.FlagSet, f *client.OrderFilter) func() error {
+	status := fs.String("status", "", "comma separated order statuses, e.g. new,partially_filled")
+	side := fs.String("side", "", "buy, sell or sell_short")
+	since := fs.String("since", "", "orders created since a RFC 3339 time or a duration ago, e.g. 15m")
+	fs.StringVar(&f.Symbol, "symbol", "", "symbol")
+	fs.StringVar(&f.Account, "account", "", "account")
+	fs.StringVar(&f.Session, "session", "", "session id")
+
+	return func() (err error) {
+		if *status != "" {
+			for _, s := range strings.Split(*status, ",") {
+				ordStatus, err := parseEnum("status", strings.TrimSpace(s), ordStatuses)
+				if err != nil {
+					return err
+				}
+				f.Status = append(f.Status, ordStatus)
+			}
+		}
+
+		if f.Side, err = parseEnum("side", *side, sides); err != nil {
+			return err
+		}
+
+		f.Since, err = parseSince(*since)
+		return err
+	}
+}
+
+func listOrders(ctx context.Context, c *client.Client, out output, args []string) error {
+	var f client.OrderFilter
+	fs := newFlagSet("orders list")
+	pageFlags(fs, &f.Page)
+	parseFilter := orderFilterFlags(fs, &f)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := parseFilter(); err != nil {
+		return err
+	}
+
+	orders, next, err := c.Orders(ctx, f)
+	if err != nil {
+		return err
+	}
+
+	return out.orders(orders, next)
+}
+
+func newOrder(ctx context.Context, c *client.Client, out output, args []string) error {
+	var order oms.Order
+	fs := newFlagSet("orders new")
+	fs.StringVar(&order.Session, "session", "", "session id to send the order on")
+	fs.StringVar(&order.Symbol, "symbol", "", "symbol")
+	fs.StringVar(&order.Account, "account", "", "account")
+	fs.StringVar(&order.Quantity, "qty", "", "order quantity")
+	fs.StringVar(&order.Price, "price", "", "limit price")
+	fs.StringVar(&order.StopPrice, "stop-price", "", "stop price")
+	fs.StringVar(&order.QuoteID, "quote-id", "", "quote id of a previously quoted order")
+	side := fs.String("side", "", "buy, sell or sell_short")
+	ordType := fs.String("type", "market", "market, limit, stop, stop_limit or previously_quoted")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var err error
+	if order.Side, err = parseEnum("side", *side, sides); err != nil {
+		return err
+	}
+	if order.OrdType, err = parseEnum("type", *ordType, ordTypes); err != nil {
+		return err
+	}
+
+	switch {
+	case order.Session == "":
+		return errors.New("orders new: -session is required")
+	case order.Symbol == "":
+		return errors.New("orders new: -symbol is required")
+	case order.Side == "":
+		return errors.New("orders new: -side is required")
+	case order.Quantity == "":
+		return errors.New("orders new: -qty is required")
+	}
+
+	sent, err := c.NewOrder(ctx, order)
+	if err != nil {
+		return err
+	}
+
+	return out.order(sent)
+}
+
+func cancelOrder(ctx context.Context, c *client.Client, out output, args []string) error {
+	id, err := parseWithID(newFlagSet("orders cancel"), args)
+	if err != nil {
+		return err
+	}
+
+	order, err := c.CancelOrder(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return out.order(order)
+}
+
+func amendOrder(ctx context.Context, c *client.Client, out output, args []string) error {
+	var amendment client.OrderAmendment
+	fs := newFlagSet("orders amend")
+	fs.StringVar(&amendment.Quantity, "qty", "", "new order quantity")
+	fs.StringVar(&amendment.Price, "price", "", "new limit price")
+	fs.StringVar(&amendment.StopPrice, "stop-price", "", "new stop price")
+	id, err := parseWithID(fs, args)
+	if err != nil {
+		return err
+	}
+
+	if amendment == (client.OrderAmendment{}) {
+		return errors.New("orders amend: nothing to amend, set -qty, -price or -stop-price")
+	}
+
+	order, err := c.AmendOrder(ctx, id, amendment)
+	if err != nil {
+		return err
+	}
+
+	return out.order(order)
+}
+
+func listExecutions(ctx context.Context, c *client.Client, out output, args []string) error {
+	var f client.ExecutionFilter
+	fs := newFlagSet("executions list")
+	pageFlags(fs, &f.Page)
+	fs.StringVar(&f.Symbol, "symbol", "", "symbol")
+	fs.StringVar(&f.Session, "session", "", "session id")
+	side := fs.String("side", "", "buy, sell or sell_short")
+	since := fs.String("since", "", "executions since a RFC 3339 time or a duration ago, e.g. 15m")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var err error
+	if f.Side, err = parseEnum("side", *side, sides); err != nil {
+		return err
+	}
+	if f.Since, err = parseSince(*since); err != nil {
+		return err
+	}
+
+	executions, next, err := c.Executions(ctx, f)
+	if err != nil {
+		return err
+	}
+
+	return out.executions(executions, next)
+}
+
+func listSessions(ctx context.Context, c *client.Client, out output, args []string) error {
+	if err := newFlagSet("sessions list").Parse(args); err != nil {
+		return err
+	}
+
+	sessions, err := c.Sessions(ctx)
+	if err != nil {
+		return err
+	}
+
+	return out.sessions(sessions)
+}
+
+func requestSecDef(ctx context.Context, c *client.Client, out output, args []string) error {
+	var req secmaster.SecurityDefinitionRequest
+	fs := newFlagSet("secdef request")
+	fs.StringVar(&req.Session, "session", "", "session id to send the request on")
+	fs.StringVar(&req.Symbol, "symbol", "", "symbol")
+	securityType := fs.String("security-type", "", "security type, e.g. CS")
+	requestType := fs.String("request-type", string(enum.SecurityRequestType_REQUEST_SECURITY_IDENTITY_AND_SPECIFICATIONS), "security request type")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if req.Session == "" {
+		return errors.New("secdef request: -session is required")
+	}
+	req.SecurityType = enum.SecurityType(*securityType)
+	req.SecurityRequestType = enum.SecurityRequestType(*requestType)
+
+	if err := c.SecurityDefinitionRequest(ctx, req); err != nil {
+		return err
+	}
+
+	if out.json {
+		return out.writeJSON(req)
+	}
+	_, err := fmt.Fprintf(out.w, "security definition request for %v sent on %v\n", req.Symbol, req.Session)
+	return err
+}
+
+// orderState is what watch compares to detect updates of an order
+type orderState struct {
+	ClOrdID, Quantity, Price, StopPrice, Open, Closed, AvgPx, RejectReason string
+	OrdStatus                                                              enum.OrdStatus
+}
+
+func stateOf(order *oms.Order) orderState {
+	return orderState{order.ClOrdID, order.Quantity, order.Price, order.StopPrice, order.Open, order.Closed, order.AvgPx, order.RejectReason, order.OrdStatus}
+}
+
+// watch polls the orders and prints every order that is new or changed since the last poll,
+// until interrupted
+func watch(ctx context.Context, c *client.Client, out output, args []string) error {
+	var f client.OrderFilter
+	fs := newFlagSet("watch")
+	interval := fs.Duration("interval", time.Second, "polling interval")
+	parseFilter := orderFilterFlags(fs, &f)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := parseFilter(); err != nil {
+		return err
+	}
+
+	seen := make(map[int]orderState)
+	header := true
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+
+	for {
+		changed, err := pollOrders(ctx, c, f, seen)
+		if err != nil && ctx.Err() == nil {
+			return err
+		}
+
+		if err == nil && len(changed) > 0 {
+			if err = out.orderUpdates(changed, header); err != nil {
+				return err
+			}
+			header = false
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// pollOrders fetches every page of orders matching f, returning those changed since seen
+func pollOrders(ctx context.Context, c *client.Client, f client.OrderFilter, seen map[int]orderState) ([]*oms.Order, error) {
+	var changed []*oms.Order
+	f.Page = client.Page{Limit: 500}
+	for {
+		orders, next, err := c.Orders(ctx, f)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, order := range orders {
+			state := stateOf(order)
+			if prev, ok := seen[order.ID]; !ok || prev != state {
+				seen[order.ID] = state
+				changed = append(changed, order)
+			}
+		}
+
+		if next == 0 {
+			return changed, nil
+		}
+		f.Cursor = next
+	}
+}
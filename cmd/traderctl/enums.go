@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/quickfixgo/enum"
+)
+
+var sides = map[string]enum.Side{
+	"buy":        enum.Side_BUY,
+	"sell":       enum.Side_SELL,
+	"sell_short": enum.Side_SELL_SHORT,
+}
+
+var ordTypes = map[string]enum.OrdType{
+	"market":            enum.OrdType_MARKET,
+	"limit":             enum.OrdType_LIMIT,
+	"stop":              enum.OrdType_STOP,
+	"stop_limit":        enum.OrdType_STOP_LIMIT,
+	"previously_quoted": enum.OrdType_PREVIOUSLY_QUOTED,
+}
+
+var ordStatuses = map[string]enum.OrdStatus{
+	"new":              enum.OrdStatus_NEW,
+	"partially_filled": enum.OrdStatus_PARTIALLY_FILLED,
+	"filled":           enum.OrdStatus_FILLED,
+	"done_for_day":     enum.OrdStatus_DONE_FOR_DAY,
+	"canceled":         enum.OrdStatus_CANCELED,
+	"replaced":         enum.OrdStatus_REPLACED,
+	"pending_cancel":   enum.OrdStatus_PENDING_CANCEL,
+	"stopped":          enum.OrdStatus_STOPPED,
+	"rejected":         enum.OrdStatus_REJECTED,
+	"suspended":        enum.OrdStatus_SUSPENDED,
+	"pending_new":      enum.OrdStatus_PENDING_NEW,
+	"calculated":       enum.OrdStatus_CALCULATED,
+	"expired":          enum.OrdStatus_EXPIRED,
+	"pending_replace":  enum.OrdStatus_PENDING_REPLACE,
+}
+
+// parseEnum accepts a value by name, case insensitive, or by its FIX code
+func parseEnum[T ~string](kind, s string, names map[string]T) (T, error) {
+	if s == "" {
+		return "", nil
+	}
+
+	if v, ok := names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+
+	for _, v := range names {
+		if string(v) == s {
+			return v, nil
+		}
+	}
+
+	return "", fmt.Errorf("invalid %v %v", kind, s)
+}
+
+// enumName returns the name of a FIX code, or the code if it has no name
+func enumName[T ~string](v T, names map[string]T) string {
+	for name, code := range names {
+		if code == v {
+			return name
+		}
+	}
+	return string(v)
+}
+
+func sideName(v enum.Side) string           { return enumName(v, sides) }
+func ordTypeName(v enum.OrdType) string     { return enumName(v, ordTypes) }
+func ordStatusName(v enum.OrdStatus) string { return enumName(v, ordStatuses) }
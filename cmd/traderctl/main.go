@@ -0,0 +1,143 @@
+// Command traderctl manages orders through the traderui REST API.
+//
+//	traderctl [-url http://localhost:8080] [-token t | -user u -password p] [-json] <command> [args]
+//
+// Commands:
+//
+//	orders list [-status new,partially_filled] [-symbol s] [-account a] [-session s] [-side buy] [-since t] [-sort f] [-limit n] [-cursor c]
+//	orders new -session s -symbol s -side buy -qty 100 [-type limit -price 10.5] [-stop-price p] [-account a]
+//	orders cancel <id>
+//	orders amend <id> [-qty q] [-price p] [-stop-price p]
+//	executions list [-symbol s] [-session s] [-side sell] [-since t] [-sort f] [-limit n] [-cursor c]
+//	sessions list
+//	secdef request -session s -symbol s [-security-type CS] [-request-type 0]
+//	watch [-interval 1s] [-status ...] [-symbol s] [-account a] [-session s] [-side buy]
+//
+// Output is a table, or json with -json. watch prints orders as they change, one json object per
+// line with -json.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+
+	"github.com/quickfixgo/traderui/client"
+)
+
+// command runs a subcommand with its arguments
+type command func(ctx context.Context, c *client.Client, out output, args []string) error
+
+var commands = map[string]map[string]command{
+	"orders": {
+		"list":   listOrders,
+		"new":    newOrder,
+		"cancel": cancelOrder,
+		"amend":  amendOrder,
+	},
+	"executions": {"list": listExecutions},
+	"sessions":   {"list": listSessions},
+	"secdef":     {"request": requestSecDef},
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: traderctl [flags] <command> [args]")
+	fmt.Fprintln(os.Stderr, "\ncommands:")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		subs := make([]string, 0, len(commands[name]))
+		for sub := range commands[name] {
+			subs = append(subs, sub)
+		}
+		sort.Strings(subs)
+		fmt.Fprintf(os.Stderr, "  %v %v\n", name, strings.Join(subs, "|"))
+	}
+	fmt.Fprintln(os.Stderr, "  watch")
+	fmt.Fprintln(os.Stderr, "\nflags:")
+	flag.PrintDefaults()
+}
+
+func main() {
+	os.Exit(run())
+}
+
+// run executes the command line, returning the exit code
+func run() int {
+	baseURL := flag.String("url", envOr("TRADERUI_URL", "http://localhost:8080"), "base url of the traderui server")
+	token := flag.String("token", os.Getenv("TRADERUI_TOKEN"), "bearer token of an existing session")
+	user := flag.String("user", os.Getenv("TRADERUI_USER"), "username to log in with, instead of -token")
+	password := flag.String("password", os.Getenv("TRADERUI_PASSWORD"), "password to log in with")
+	asJSON := flag.Bool("json", false, "print json instead of tables")
+	flag.Usage = usage
+	flag.Parse()
+
+	cmd, args, err := lookup(flag.Args())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		usage()
+		return 2
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	c := client.New(*baseURL)
+	c.Token = *token
+	if *user != "" {
+		if _, err = c.Login(ctx, *user, *password); err != nil {
+			fmt.Fprintf(os.Stderr, "login: %v\n", err)
+			return 1
+		}
+		defer func() { _ = c.Logout(context.Background()) }()
+	}
+
+	if err = cmd(ctx, c, output{w: os.Stdout, json: *asJSON}, args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	return 0
+}
+
+// lookup returns the command named by the leading arguments and the arguments left for it
+func lookup(args []string) (command, []string, error) {
+	if len(args) == 0 {
+		return nil, nil, fmt.Errorf("missing command")
+	}
+
+	if args[0] == "watch" {
+		return watch, args[1:], nil
+	}
+
+	subs, ok := commands[args[0]]
+	if !ok {
+		return nil, nil, fmt.Errorf("unknown command %v", args[0])
+	}
+
+	if len(args) < 2 {
+		return nil, nil, fmt.Errorf("missing %v subcommand", args[0])
+	}
+
+	cmd, ok := subs[args[1]]
+	if !ok {
+		return nil, nil, fmt.Errorf("unknown command %v %v", args[0], args[1])
+	}
+
+	return cmd, args[2:], nil
+}
+
+func envOr(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
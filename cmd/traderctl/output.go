@@ -0,0 +1,142 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/gosuri/uitable"
+
+	"github.com/quickfixgo/traderui/oms"
+)
+
+// output prints results as tables, or as json
+type output struct {
+	w    io.Writer
+	json bool
+}
+
+func newTable() *uitable.Table {
+	table := uitable.New()
+	table.MaxColWidth = 50
+	table.Wrap = true // wrap columns
+	return table
+}
+
+func (o output) writeJSON(v interface{}) error {
+	enc := json.NewEncoder(o.w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func (o output) table(table *uitable.Table) error {
+	_, err := fmt.Fprintln(o.w, table)
+	return err
+}
+
+func addOrderHeader(table *uitable.Table, prefix ...interface{}) {
+	table.AddRow(append(prefix, "ID", "CLORDID", "SESSION", "ACCOUNT", "SYMBOL", "SIDE", "TYPE", "QTY", "PRICE", "STOP", "OPEN", "CLOSED", "AVGPX", "STATUS", "REJECT REASON")...)
+}
+
+func addOrderRow(table *uitable.Table, order *oms.Order, prefix ...interface{}) {
+	table.AddRow(append(prefix, order.ID, order.ClOrdID, order.Session, order.Account, order.Symbol,
+		sideName(order.Side), ordTypeName(order.OrdType), order.Quantity, order.Price, order.StopPrice,
+		order.Open, order.Closed, order.AvgPx, ordStatusName(order.OrdStatus), order.RejectReason)...)
+}
+
+func (o output) orders(orders []*oms.Order, next int) error {
+	if o.json {
+		if err := o.writeJSON(orders); err != nil {
+			return err
+		}
+		return nextPage(next)
+	}
+
+	table := newTable()
+	addOrderHeader(table)
+	for _, order := range orders {
+		addOrderRow(table, order)
+	}
+
+	if err := o.table(table); err != nil {
+		return err
+	}
+	return nextPage(next)
+}
+
+func (o output) order(order *oms.Order) error {
+	if o.json {
+		return o.writeJSON(order)
+	}
+	return o.orders([]*oms.Order{order}, 0)
+}
+
+func (o output) executions(executions []*oms.Execution, next int) error {
+	if o.json {
+		if err := o.writeJSON(executions); err != nil {
+			return err
+		}
+		return nextPage(next)
+	}
+
+	table := newTable()
+	table.AddRow("ID", "TIME", "SESSION", "SYMBOL", "SIDE", "QTY", "PRICE", "LEG")
+	for _, exec := range executions {
+		table.AddRow(exec.ID, exec.CreatedAt.Format(time.RFC3339), exec.Session, exec.Symbol,
+			sideName(exec.Side), exec.Quantity, exec.Price, exec.LegRefID)
+	}
+
+	if err := o.table(table); err != nil {
+		return err
+	}
+	return nextPage(next)
+}
+
+func (o output) sessions(sessions []string) error {
+	if o.json {
+		return o.writeJSON(sessions)
+	}
+
+	table := newTable()
+	table.AddRow("SESSION")
+	for _, s := range sessions {
+		table.AddRow(s)
+	}
+	return o.table(table)
+}
+
+// orderUpdates prints orders seen by watch, as a table or one json object per line
+func (o output) orderUpdates(orders []*oms.Order, header bool) error {
+	if o.json {
+		enc := json.NewEncoder(o.w)
+		for _, order := range orders {
+			if err := enc.Encode(order); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	now := time.Now().Format(time.TimeOnly)
+	table := newTable()
+	if header {
+		addOrderHeader(table, "TIME")
+	}
+	for _, order := range orders {
+		addOrderRow(table, order, now)
+	}
+
+	return o.table(table)
+}
+
+// nextPage tells on stderr the cursor of the next page, keeping stdout parseable
+func nextPage(next int) error {
+	if next == 0 {
+		return nil
+	}
+
+	_, err := fmt.Fprintf(os.Stderr, "more results, next page with -cursor %v\n", next)
+	return err
+}
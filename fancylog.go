@@ -2,46 +2,83 @@ package main
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/fatih/color"
 	"github.com/gosuri/uitable"
 
+	"github.com/quickfixgo/enum"
 	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/quickfix/datadictionary"
+
+	"github.com/quickfixgo/traderui/fixlog"
 )
 
+// rejects are the MsgTypes highlighted as rejects, along with rejected execution reports
+var rejects = map[string]bool{
+	string(enum.MsgType_REJECT):                  true,
+	string(enum.MsgType_BUSINESS_MESSAGE_REJECT): true,
+	string(enum.MsgType_ORDER_CANCEL_REJECT):     true,
+}
+
 type screenLog struct {
-	prefix string
+	prefix  string
+	dicts   []*datadictionary.DataDictionary
+	include map[string]bool
+	exclude map[string]bool
 }
 
-func (l screenLog) OnIncoming(s []byte) {
+// message prints the fields of msg one per row, coloured by direction unless it is a reject or
+// an execution report
+func (l screenLog) message(title string, attr color.Attribute, s []byte) {
+	fields := fixlog.Fields(s, l.dicts...)
+
+	values := make(map[int]string, len(fields))
+	for _, f := range fields {
+		values[f.Tag] = f.Value
+	}
+
+	msgType := values[35]
+	if l.exclude[msgType] || (len(l.include) > 0 && !l.include[msgType]) {
+		return
+	}
+
+	switch {
+	case rejects[msgType],
+		msgType == string(enum.MsgType_EXECUTION_REPORT) &&
+			(values[150] == string(enum.ExecType_REJECTED) || values[39] == string(enum.OrdStatus_REJECTED)):
+		title, attr = strings.Replace(title, "FIX Msg", "Reject", 1), color.FgRed
+	case msgType == string(enum.MsgType_EXECUTION_REPORT):
+		title, attr = strings.Replace(title, "FIX Msg", "Execution Report", 1), color.FgGreen
+	}
+
 	table := uitable.New()
 	table.MaxColWidth = 150
 	table.Wrap = true // wrap columns
 
 	table.AddRow(" |Time:", fmt.Sprintf("%v", time.Now().UTC()))
 	table.AddRow(" |Session:", l.prefix)
-	table.AddRow(" |Content:", string(s))
-
-	color.Set(color.Bold, color.FgBlue)
-	fmt.Println("<=== Incoming FIX Msg: <===")
+	for i, f := range fields {
+		label := ""
+		if i == 0 {
+			label = " |Content:"
+		}
+		table.AddRow(label, f.String())
+	}
+
+	color.Set(color.Bold, attr)
+	fmt.Println(title)
 	fmt.Println(table)
 	color.Unset()
 }
 
-func (l screenLog) OnOutgoing(s []byte) {
-	table := uitable.New()
-	table.MaxColWidth = 150
-	table.Wrap = true // wrap columns
-
-	table.AddRow(" |Time:", fmt.Sprintf("%v", time.Now().UTC()))
-	table.AddRow(" |Session:", l.prefix)
-	table.AddRow(" |Content:", string(s))
+func (l screenLog) OnIncoming(s []byte) {
+	l.message("<=== Incoming FIX Msg: <===", color.FgBlue, s)
+}
 
-	color.Set(color.Bold, color.FgMagenta)
-	fmt.Println("===> Outgoing FIX Msg: ===>")
-	fmt.Println(table)
-	color.Unset()
+func (l screenLog) OnOutgoing(s []byte) {
+	l.message("===> Outgoing FIX Msg: ===>", color.FgMagenta, s)
 }
 
 func (l screenLog) OnEvent(s string) {
@@ -64,19 +101,42 @@ func (l screenLog) OnEventf(format string, a ...interface{}) {
 	l.OnEvent(fmt.Sprintf(format, a...))
 }
 
-type screenLogFactory struct{}
+type screenLogFactory struct {
+	dicts   *fixlog.Dictionaries
+	include map[string]bool
+	exclude map[string]bool
+}
 
-func (screenLogFactory) Create() (quickfix.Log, error) {
-	log := screenLog{"GLOBAL"}
+func (f screenLogFactory) Create() (quickfix.Log, error) {
+	log := screenLog{prefix: "GLOBAL", include: f.include, exclude: f.exclude}
 	return log, nil
 }
 
-func (screenLogFactory) CreateSessionLog(sessionID quickfix.SessionID) (quickfix.Log, error) {
-	log := screenLog{sessionID.String()}
+func (f screenLogFactory) CreateSessionLog(sessionID quickfix.SessionID) (quickfix.Log, error) {
+	dicts, err := f.dicts.ForSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	log := screenLog{sessionID.String(), dicts, f.include, f.exclude}
 	return log, nil
 }
 
+// msgTypeSet parses a comma separated list of MsgTypes
+func msgTypeSet(list string) map[string]bool {
+	set := make(map[string]bool)
+	for _, t := range strings.Split(list, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			set[t] = true
+		}
+	}
+
+	return set
+}
+
 // NewFancyLog creates an instance of LogFactory that writes messages and events to stdout.
-func NewFancyLog() quickfix.LogFactory {
-	return screenLogFactory{}
+// Fields are named by the session's data dictionary from dicts. Only messages with a MsgType in
+// the comma separated include list, if not empty, and not in exclude are written.
+func NewFancyLog(dicts *fixlog.Dictionaries, include, exclude string) quickfix.LogFactory {
+	return screenLogFactory{dicts, msgTypeSet(include), msgTypeSet(exclude)}
 }
@@ -0,0 +1,164 @@
+package fixlog
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/quickfix/config"
+	"github.com/quickfixgo/quickfix/datadictionary"
+)
+
+// specFiles are the quickfix spec files by BeginString or ApplVerID
+var specFiles = map[string]string{
+	quickfix.BeginStringFIX40:  "FIX40.xml",
+	quickfix.BeginStringFIX41:  "FIX41.xml",
+	quickfix.BeginStringFIX42:  "FIX42.xml",
+	quickfix.BeginStringFIX43:  "FIX43.xml",
+	quickfix.BeginStringFIX44:  "FIX44.xml",
+	quickfix.BeginStringFIXT11: "FIXT11.xml",
+	"FIX.5.0":                  "FIX50.xml",
+	"FIX.5.0SP1":               "FIX50SP1.xml",
+	"FIX.5.0SP2":               "FIX50SP2.xml",
+	"7":                        "FIX50.xml",
+	"8":                        "FIX50SP1.xml",
+	"9":                        "FIX50SP2.xml",
+}
+
+// Dictionaries loads the data dictionaries naming the fields of each session's messages, from
+// the DataDictionary, TransportDataDictionary and AppDataDictionary session settings or else
+// from the quickfix spec files in a directory. Each file is parsed once.
+type Dictionaries struct {
+	mu       sync.Mutex
+	settings *quickfix.Settings
+	specDir  string
+	parsed   map[string]*datadictionary.DataDictionary
+}
+
+// NewDictionaries returns the dictionaries of the sessions in settings, specDir may be empty
+func NewDictionaries(settings *quickfix.Settings, specDir string) *Dictionaries {
+	return &Dictionaries{
+		settings: settings,
+		specDir:  specDir,
+		parsed:   make(map[string]*datadictionary.DataDictionary),
+	}
+}
+
+// paths returns the dictionary files of a session, application dictionary first
+func (d *Dictionaries) paths(sessionID quickfix.SessionID) []string {
+	var settings *quickfix.SessionSettings
+	if d.settings != nil {
+		settings = d.settings.SessionSettings()[sessionID]
+	}
+
+	setting := func(name string) string {
+		if settings == nil || !settings.HasSetting(name) {
+			return ""
+		}
+		v, _ := settings.Setting(name)
+		return v
+	}
+
+	if sessionID.BeginString != quickfix.BeginStringFIXT11 {
+		if path := setting(config.DataDictionary); path != "" {
+			return []string{path}
+		}
+		if d.specDir == "" || specFiles[sessionID.BeginString] == "" {
+			return nil
+		}
+		return []string{filepath.Join(d.specDir, specFiles[sessionID.BeginString])}
+	}
+
+	app, transport := setting(config.AppDataDictionary), setting(config.TransportDataDictionary)
+	if d.specDir != "" {
+		if app == "" && specFiles[setting(config.DefaultApplVerID)] != "" {
+			app = filepath.Join(d.specDir, specFiles[setting(config.DefaultApplVerID)])
+		}
+		if transport == "" {
+			transport = filepath.Join(d.specDir, specFiles[quickfix.BeginStringFIXT11])
+		}
+	}
+
+	var paths []string
+	for _, path := range []string{app, transport} {
+		if path != "" {
+			paths = append(paths, path)
+		}
+	}
+
+	return paths
+}
+
+// ForSession returns the dictionaries of a session, none if neither the settings nor the spec
+// directory provide them
+func (d *Dictionaries) ForSession(sessionID quickfix.SessionID) ([]*datadictionary.DataDictionary, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var dicts []*datadictionary.DataDictionary
+	for _, path := range d.paths(sessionID) {
+		dict, ok := d.parsed[path]
+		if !ok {
+			var err error
+			if dict, err = datadictionary.Parse(path); err != nil {
+				return nil, fmt.Errorf("data dictionary of %v: %v", sessionID, err)
+			}
+			d.parsed[path] = dict
+		}
+		dicts = append(dicts, dict)
+	}
+
+	return dicts, nil
+}
+
+// Field is a field of a raw message, named by a data dictionary if one defines it
+type Field struct {
+	Tag   int
+	Value string
+	Name  string
+	Enum  string
+}
+
+// String renders the field as Name(tag)=Value(Enum), leaving out unknown names
+func (f Field) String() string {
+	var b strings.Builder
+	b.WriteString(f.Name)
+	fmt.Fprintf(&b, "(%v)=%v", f.Tag, f.Value)
+	if f.Enum != "" {
+		fmt.Fprintf(&b, "(%v)", f.Enum)
+	}
+
+	return b.String()
+}
+
+// Fields splits a raw message into its fields, named by the first of dicts defining each tag
+func Fields(msg []byte, dicts ...*datadictionary.DataDictionary) []Field {
+	var fields []Field
+	for _, tv := range bytes.Split(bytes.TrimSuffix(msg, []byte("\x01")), []byte("\x01")) {
+		tagValue := strings.SplitN(string(tv), "=", 2)
+		if len(tagValue) != 2 {
+			continue
+		}
+
+		tag, err := strconv.Atoi(tagValue[0])
+		if err != nil {
+			continue
+		}
+
+		f := Field{Tag: tag, Value: tagValue[1]}
+		for _, dict := range dicts {
+			if fieldType, ok := dict.FieldTypeByTag[tag]; ok {
+				f.Name = fieldType.Name()
+				f.Enum = fieldType.Enums[f.Value].Description
+				break
+			}
+		}
+		fields = append(fields, f)
+	}
+
+	return fields
+}
@@ -0,0 +1,70 @@
+package fixlog
+
+import (
+	"fmt"
+
+	"github.com/quickfixgo/enum"
+	"github.com/quickfixgo/quickfix"
+)
+
+// Heartbeats are the MsgTypes of heartbeats and test requests
+var Heartbeats = []enum.MsgType{enum.MsgType_HEARTBEAT, enum.MsgType_TEST_REQUEST}
+
+type filterLog struct {
+	skip map[string]bool
+	next quickfix.Log
+}
+
+func (l filterLog) OnIncoming(msg []byte) {
+	if !l.skip[msgType(msg)] {
+		l.next.OnIncoming(msg)
+	}
+}
+
+func (l filterLog) OnOutgoing(msg []byte) {
+	if !l.skip[msgType(msg)] {
+		l.next.OnOutgoing(msg)
+	}
+}
+
+func (l filterLog) OnEvent(s string) {
+	l.next.OnEvent(s)
+}
+
+func (l filterLog) OnEventf(format string, a ...interface{}) {
+	l.OnEvent(fmt.Sprintf(format, a...))
+}
+
+type filterLogFactory struct {
+	skip map[string]bool
+	next quickfix.LogFactory
+}
+
+func (f filterLogFactory) Create() (quickfix.Log, error) {
+	next, err := f.next.Create()
+	if err != nil {
+		return nil, err
+	}
+
+	return filterLog{f.skip, next}, nil
+}
+
+func (f filterLogFactory) CreateSessionLog(sessionID quickfix.SessionID) (quickfix.Log, error) {
+	next, err := f.next.CreateSessionLog(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return filterLog{f.skip, next}, nil
+}
+
+// Skip returns a LogFactory that passes messages on to next unless their MsgType is one of
+// msgTypes. Events are always passed on.
+func Skip(next quickfix.LogFactory, msgTypes ...enum.MsgType) quickfix.LogFactory {
+	skip := make(map[string]bool)
+	for _, t := range msgTypes {
+		skip[string(t)] = true
+	}
+
+	return filterLogFactory{skip, next}
+}
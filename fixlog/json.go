@@ -0,0 +1,116 @@
+// Package fixlog provides quickfix log factories for log shippers: one json object per line,
+// teeing to several factories and filtering session level noise.
+package fixlog
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+// Direction is what a json log line records
+type Direction string
+
+// Directions of a log line
+const (
+	Incoming Direction = "incoming"
+	Outgoing Direction = "outgoing"
+	Event    Direction = "event"
+)
+
+// Line is one json log line. Message fields are empty for events.
+type Line struct {
+	Time      time.Time `json:"time"`
+	Session   string    `json:"session"`
+	Direction Direction `json:"direction"`
+	MsgType   string    `json:"msg_type,omitempty"`
+	SeqNum    string    `json:"seq_num,omitempty"`
+	ClOrdID   string    `json:"cl_ord_id,omitempty"`
+	Message   string    `json:"message,omitempty"`
+	Event     string    `json:"event,omitempty"`
+}
+
+// fieldValue returns the value of tag in a raw message, empty if absent
+func fieldValue(msg []byte, tag string) string {
+	prefix := []byte("\x01" + tag + "=")
+	i := bytes.Index(msg, prefix)
+	if i < 0 {
+		return ""
+	}
+
+	rest := msg[i+len(prefix):]
+	if end := bytes.IndexByte(rest, '\x01'); end >= 0 {
+		rest = rest[:end]
+	}
+
+	return string(rest)
+}
+
+// msgType returns the MsgType(35) of a raw message
+func msgType(msg []byte) string {
+	return fieldValue(msg, "35")
+}
+
+type jsonLog struct {
+	session string
+	factory *jsonLogFactory
+}
+
+func (l jsonLog) message(direction Direction, msg []byte) {
+	l.factory.write(Line{
+		Session:   l.session,
+		Direction: direction,
+		MsgType:   msgType(msg),
+		SeqNum:    fieldValue(msg, "34"),
+		ClOrdID:   fieldValue(msg, "11"),
+		Message:   string(msg),
+	})
+}
+
+func (l jsonLog) OnIncoming(msg []byte) {
+	l.message(Incoming, msg)
+}
+
+func (l jsonLog) OnOutgoing(msg []byte) {
+	l.message(Outgoing, msg)
+}
+
+func (l jsonLog) OnEvent(s string) {
+	l.factory.write(Line{Session: l.session, Direction: Event, Event: s})
+}
+
+func (l jsonLog) OnEventf(format string, a ...interface{}) {
+	l.OnEvent(fmt.Sprintf(format, a...))
+}
+
+// jsonLogFactory serializes the lines of all its logs to one writer
+type jsonLogFactory struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+func (f *jsonLogFactory) write(line Line) {
+	line.Time = time.Now().UTC()
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	_ = f.enc.Encode(line)
+}
+
+func (f *jsonLogFactory) Create() (quickfix.Log, error) {
+	return jsonLog{"GLOBAL", f}, nil
+}
+
+func (f *jsonLogFactory) CreateSessionLog(sessionID quickfix.SessionID) (quickfix.Log, error) {
+	return jsonLog{sessionID.String(), f}, nil
+}
+
+// NewJSONLogFactory returns a LogFactory writing messages and events to w as json lines
+func NewJSONLogFactory(w io.Writer) quickfix.LogFactory {
+	return &jsonLogFactory{enc: json.NewEncoder(w)}
+}
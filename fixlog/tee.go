@@ -0,0 +1,68 @@
+package fixlog
+
+import (
+	"fmt"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+type teeLog []quickfix.Log
+
+func (t teeLog) OnIncoming(msg []byte) {
+	for _, l := range t {
+		l.OnIncoming(msg)
+	}
+}
+
+func (t teeLog) OnOutgoing(msg []byte) {
+	for _, l := range t {
+		l.OnOutgoing(msg)
+	}
+}
+
+func (t teeLog) OnEvent(s string) {
+	for _, l := range t {
+		l.OnEvent(s)
+	}
+}
+
+func (t teeLog) OnEventf(format string, a ...interface{}) {
+	t.OnEvent(fmt.Sprintf(format, a...))
+}
+
+type teeLogFactory []quickfix.LogFactory
+
+func (t teeLogFactory) Create() (quickfix.Log, error) {
+	logs := make(teeLog, 0, len(t))
+	for _, f := range t {
+		l, err := f.Create()
+		if err != nil {
+			return nil, err
+		}
+		logs = append(logs, l)
+	}
+
+	return logs, nil
+}
+
+func (t teeLogFactory) CreateSessionLog(sessionID quickfix.SessionID) (quickfix.Log, error) {
+	logs := make(teeLog, 0, len(t))
+	for _, f := range t {
+		l, err := f.CreateSessionLog(sessionID)
+		if err != nil {
+			return nil, err
+		}
+		logs = append(logs, l)
+	}
+
+	return logs, nil
+}
+
+// Tee returns a LogFactory whose logs write to the logs of every factory
+func Tee(factories ...quickfix.LogFactory) quickfix.LogFactory {
+	if len(factories) == 1 {
+		return factories[0]
+	}
+
+	return teeLogFactory(factories)
+}
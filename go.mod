@@ -17,6 +17,8 @@ require (
 	github.com/quickfixgo/quickfix v0.9.0
 	github.com/quickfixgo/tag v0.1.0
 	github.com/shopspring/decimal v1.3.1
+	golang.org/x/crypto v0.26.0
+	google.golang.org/grpc v1.67.1
 )
 
 require (
@@ -27,6 +29,9 @@ require (
 	github.com/pkg/errors v0.9.1 // indirect
 	github.com/quickfixgo/fixt11 v0.1.0 // indirect
 	github.com/rivo/uniseg v0.4.4 // indirect
-	golang.org/x/net v0.18.0 // indirect
-	golang.org/x/sys v0.14.0 // indirect
+	golang.org/x/net v0.28.0 // indirect
+	golang.org/x/sys v0.24.0 // indirect
+	golang.org/x/text v0.17.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20240814211410-ddb44dafa142 // indirect
+	google.golang.org/protobuf v1.34.2 // indirect
 )
@@ -0,0 +1,255 @@
+package main
+
+import (
+	"context"
+	"net"
+	"net/http"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+
+	"github.com/quickfixgo/traderui/audit"
+	"github.com/quickfixgo/traderui/auth"
+	"github.com/quickfixgo/traderui/grpcapi"
+	"github.com/quickfixgo/traderui/oms"
+)
+
+// orderService implements the gRPC api with the same tradeClient logic as the http handlers
+type orderService struct {
+	c *tradeClient
+	// shutdown ends the subscription streams, so the server can stop gracefully
+	shutdown context.Context
+}
+
+// request adapts ctx to the authorization and audit helpers shared with the http handlers, which
+// only read the request context
+func request(ctx context.Context) *http.Request {
+	return new(http.Request).WithContext(ctx)
+}
+
+// snapshot copies order for encoding outside of the order manager lock
+func (s orderService) snapshot(order *oms.Order) *oms.Order {
+	s.c.RLock()
+	defer s.c.RUnlock()
+
+	snapshot := order.Snapshot()
+	return &snapshot
+}
+
+// order returns the order with id if the user may see it
+func (s orderService) order(r *http.Request, id int) (*oms.Order, error) {
+	s.c.RLock()
+	order, err := s.c.Get(id)
+	s.c.RUnlock()
+	if err != nil || !auth.CanView(r, order.Session, order.Account) {
+		return nil, status.Error(codes.NotFound, "Order not found")
+	}
+
+	return order, nil
+}
+
+// authorizeModify checks the user may perform action on the order
+func authorizeModify(r *http.Request, action auth.Action, order *oms.Order) error {
+	if err := auth.Authorize(r, action, order.Session, order.Account); err != nil {
+		return status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	if !auth.CanModify(r, order.User) {
+		return status.Error(codes.PermissionDenied, "Forbidden")
+	}
+
+	return nil
+}
+
+func (s orderService) SubmitOrder(ctx context.Context, order *oms.Order) (*oms.Order, error) {
+	r := request(ctx)
+	if err := auth.Authorize(r, auth.ActionNew, order.Session, order.Account); err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	order.User = auth.Username(r)
+	if err := s.c.validateOrder(order); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	err := s.c.SendOrder(order)
+	audit.AddClOrdIDs(r, order.ClOrdID)
+	if err != nil {
+		return nil, sendError(err, codes.Internal)
+	}
+
+	return s.snapshot(order), nil
+}
+
+func (s orderService) CancelOrder(ctx context.Context, req *grpcapi.OrderRequest) (*oms.Order, error) {
+	r := request(ctx)
+	order, err := s.order(r, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = authorizeModify(r, auth.ActionCancel, order); err != nil {
+		return nil, err
+	}
+
+	audit.AddClOrdIDs(r, order.ClOrdID)
+	if err = s.c.cancel(order); err != nil {
+		return nil, sendError(err, codes.Internal)
+	}
+
+	return s.snapshot(order), nil
+}
+
+func (s orderService) ReplaceOrder(ctx context.Context, req *grpcapi.ReplaceOrderRequest) (*oms.Order, error) {
+	r := request(ctx)
+	order, err := s.order(r, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = authorizeModify(r, auth.ActionAmend, order); err != nil {
+		return nil, err
+	}
+
+	clOrdID, err := s.c.ReplaceOrder(order, orderAmendment{Quantity: req.Quantity, Price: req.Price, StopPrice: req.StopPrice})
+	if err != nil {
+		return nil, sendError(err, codes.InvalidArgument)
+	}
+	audit.AddClOrdIDs(r, order.ClOrdID, clOrdID)
+
+	return s.snapshot(order), nil
+}
+
+func (s orderService) ListOrders(ctx context.Context, req *grpcapi.ListOrdersRequest) (*grpcapi.ListOrdersResponse, error) {
+	r := request(ctx)
+	q := oms.OrderQuery{
+		Status:  req.Status,
+		Symbol:  req.Symbol,
+		Account: req.Account,
+		Session: req.Session,
+		Side:    req.Side,
+		Since:   req.Since,
+		Sort:    req.Sort,
+		Limit:   req.Limit,
+		After:   req.Cursor,
+		Allow:   func(order *oms.Order) bool { return auth.CanView(r, order.Session, order.Account) },
+	}
+
+	s.c.RLock()
+	defer s.c.RUnlock()
+
+	orders, next, err := s.c.QueryOrders(q)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	resp := &grpcapi.ListOrdersResponse{Orders: make([]*oms.Order, 0, len(orders)), NextCursor: next}
+	for _, order := range orders {
+		snapshot := order.Snapshot()
+		resp.Orders = append(resp.Orders, &snapshot)
+	}
+
+	return resp, nil
+}
+
+var (
+	// errFellBehind ends a stream whose client does not keep up with the updates
+	errFellBehind = status.Error(codes.ResourceExhausted, "subscriber fell behind, resubscribe and list to resync")
+	// errShutdown ends the streams when the server shuts down
+	errShutdown = status.Error(codes.Unavailable, "server shutting down")
+)
+
+func (s orderService) SubscribeOrderEvents(req *grpcapi.SubscribeRequest, stream grpcapi.OrderService_SubscribeOrderEventsServer) error {
+	r := request(stream.Context())
+	sub := s.c.SubscribeOrders()
+	defer sub.Close()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-s.shutdown.Done():
+			return errShutdown
+		case order, ok := <-sub.C:
+			if !ok {
+				return errFellBehind
+			}
+
+			if !req.Matches(order.Symbol, order.Session) || !auth.CanView(r, order.Session, order.Account) {
+				continue
+			}
+
+			if err := stream.Send(&order); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (s orderService) SubscribeExecutions(req *grpcapi.SubscribeRequest, stream grpcapi.OrderService_SubscribeExecutionsServer) error {
+	r := request(stream.Context())
+	sub := s.c.SubscribeExecutions()
+	defer sub.Close()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-s.shutdown.Done():
+			return errShutdown
+		case exec, ok := <-sub.C:
+			if !ok {
+				return errFellBehind
+			}
+
+			if !req.Matches(exec.Symbol, exec.Session) || !auth.CanView(r, exec.Session, exec.Account) {
+				continue
+			}
+
+			if err := stream.Send(&exec); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// newGRPCServer returns the gRPC server of the order service, secured and authenticated as the
+// http server is. Subscriptions end when ctx is done.
+func (c *tradeClient) newGRPCServer(ctx context.Context, srvCfg serverConfig) (*grpc.Server, error) {
+	var opts []grpc.ServerOption
+
+	tlsConfig, err := srvCfg.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+	if tlsConfig != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+
+	var unary []grpc.UnaryServerInterceptor
+	if c.authenticator != nil {
+		unary = append(unary, grpcapi.UnaryAuthInterceptor(c.authenticator))
+		opts = append(opts, grpc.StreamInterceptor(grpcapi.StreamAuthInterceptor(c.authenticator)))
+	}
+	if c.auditLog != nil {
+		unary = append(unary, grpcapi.AuditInterceptor(c.auditLog))
+	}
+	opts = append(opts, grpc.ChainUnaryInterceptor(unary...))
+
+	server := grpc.NewServer(opts...)
+	grpcapi.RegisterOrderServiceServer(server, orderService{c, ctx})
+
+	return server, nil
+}
+
+// serveGRPC blocks serving the gRPC api on addr until the server is stopped
+func serveGRPC(server *grpc.Server, addr string) error {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	return server.Serve(lis)
+}
@@ -0,0 +1,124 @@
+package grpcapi
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+
+	"github.com/quickfixgo/traderui/oms"
+)
+
+// OrderServiceClient is the client api of the order service
+type OrderServiceClient interface {
+	SubmitOrder(ctx context.Context, in *oms.Order, opts ...grpc.CallOption) (*oms.Order, error)
+	CancelOrder(ctx context.Context, in *OrderRequest, opts ...grpc.CallOption) (*oms.Order, error)
+	ReplaceOrder(ctx context.Context, in *ReplaceOrderRequest, opts ...grpc.CallOption) (*oms.Order, error)
+	ListOrders(ctx context.Context, in *ListOrdersRequest, opts ...grpc.CallOption) (*ListOrdersResponse, error)
+	SubscribeOrderEvents(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (OrderService_SubscribeOrderEventsClient, error)
+	SubscribeExecutions(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (OrderService_SubscribeExecutionsClient, error)
+}
+
+// OrderService_SubscribeOrderEventsClient receives order snapshots
+type OrderService_SubscribeOrderEventsClient interface {
+	Recv() (*oms.Order, error)
+	grpc.ClientStream
+}
+
+// OrderService_SubscribeExecutionsClient receives executions
+type OrderService_SubscribeExecutionsClient interface {
+	Recv() (*oms.Execution, error)
+	grpc.ClientStream
+}
+
+type orderServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+// NewOrderServiceClient returns a client of the order service on cc. Calls use the json codec.
+func NewOrderServiceClient(cc grpc.ClientConnInterface) OrderServiceClient {
+	return &orderServiceClient{cc}
+}
+
+func (c *orderServiceClient) invoke(ctx context.Context, method string, in, out interface{}, opts []grpc.CallOption) error {
+	opts = append([]grpc.CallOption{grpc.CallContentSubtype(CodecName)}, opts...)
+	return c.cc.Invoke(ctx, "/"+ServiceName+"/"+method, in, out, opts...)
+}
+
+func (c *orderServiceClient) SubmitOrder(ctx context.Context, in *oms.Order, opts ...grpc.CallOption) (*oms.Order, error) {
+	out := new(oms.Order)
+	return out, c.invoke(ctx, "SubmitOrder", in, out, opts)
+}
+
+func (c *orderServiceClient) CancelOrder(ctx context.Context, in *OrderRequest, opts ...grpc.CallOption) (*oms.Order, error) {
+	out := new(oms.Order)
+	return out, c.invoke(ctx, "CancelOrder", in, out, opts)
+}
+
+func (c *orderServiceClient) ReplaceOrder(ctx context.Context, in *ReplaceOrderRequest, opts ...grpc.CallOption) (*oms.Order, error) {
+	out := new(oms.Order)
+	return out, c.invoke(ctx, "ReplaceOrder", in, out, opts)
+}
+
+func (c *orderServiceClient) ListOrders(ctx context.Context, in *ListOrdersRequest, opts ...grpc.CallOption) (*ListOrdersResponse, error) {
+	out := new(ListOrdersResponse)
+	return out, c.invoke(ctx, "ListOrders", in, out, opts)
+}
+
+// streamClient receives messages of type T on a server stream
+type streamClient[T any] struct {
+	grpc.ClientStream
+}
+
+func (s streamClient[T]) Recv() (*T, error) {
+	m := new(T)
+	if err := s.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// subscribe opens the server stream with index i of ServiceDesc and sends the request
+func (c *orderServiceClient) subscribe(ctx context.Context, i int, in *SubscribeRequest, opts []grpc.CallOption) (grpc.ClientStream, error) {
+	desc := &ServiceDesc.Streams[i]
+	opts = append([]grpc.CallOption{grpc.CallContentSubtype(CodecName)}, opts...)
+	stream, err := c.cc.NewStream(ctx, desc, "/"+ServiceName+"/"+desc.StreamName, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = stream.SendMsg(in); err != nil {
+		return nil, err
+	}
+
+	return stream, stream.CloseSend()
+}
+
+func (c *orderServiceClient) SubscribeOrderEvents(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (OrderService_SubscribeOrderEventsClient, error) {
+	stream, err := c.subscribe(ctx, 0, in, opts)
+	if err != nil {
+		return nil, err
+	}
+	return streamClient[oms.Order]{stream}, nil
+}
+
+func (c *orderServiceClient) SubscribeExecutions(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (OrderService_SubscribeExecutionsClient, error) {
+	stream, err := c.subscribe(ctx, 1, in, opts)
+	if err != nil {
+		return nil, err
+	}
+	return streamClient[oms.Execution]{stream}, nil
+}
+
+// BearerToken authenticates every call with token, as returned by POST /login
+type BearerToken string
+
+// GetRequestMetadata implements credentials.PerRPCCredentials
+func (t BearerToken) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + string(t)}, nil
+}
+
+// RequireTransportSecurity implements credentials.PerRPCCredentials. Tokens may be sent without
+// tls, as with the http api.
+func (t BearerToken) RequireTransportSecurity() bool {
+	return false
+}
@@ -0,0 +1,21 @@
+package grpcapi
+
+import (
+	"encoding/json"
+
+	"google.golang.org/grpc/encoding"
+)
+
+// CodecName is the content subtype of the api, messages are json encoded as in the REST api. Calls
+// without it are decoded as protobuf and fail, as the messages are plain Go structs.
+const CodecName = "json"
+
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error)      { return json.Marshal(v) }
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error { return json.Unmarshal(data, v) }
+func (jsonCodec) Name() string                               { return CodecName }
+
+func init() {
+	encoding.RegisterCodec(jsonCodec{})
+}
@@ -0,0 +1,106 @@
+package grpcapi
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/quickfixgo/traderui/audit"
+	"github.com/quickfixgo/traderui/auth"
+)
+
+// authenticate returns ctx carrying the user of the bearer token in the authorization metadata
+func authenticate(ctx context.Context, a *auth.Authenticator) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if headers := md.Get("authorization"); len(headers) == 1 {
+		if token, ok := strings.CutPrefix(headers[0], "Bearer "); ok {
+			if ctx, ok := a.AuthenticateToken(ctx, token); ok {
+				return ctx, nil
+			}
+		}
+	}
+
+	return ctx, status.Error(codes.Unauthenticated, "Unauthorized")
+}
+
+// UnaryAuthInterceptor rejects calls without a valid bearer token, as the http api does
+func UnaryAuthInterceptor(a *auth.Authenticator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := authenticate(ctx, a)
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// contextStream overrides the context of a stream
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s contextStream) Context() context.Context {
+	return s.ctx
+}
+
+// StreamAuthInterceptor rejects streams without a valid bearer token
+func StreamAuthInterceptor(a *auth.Authenticator) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(stream.Context(), a)
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, contextStream{stream, ctx})
+	}
+}
+
+// readOnly are the methods not recorded to the audit log, as GET requests are not
+var readOnly = map[string]bool{
+	"/" + ServiceName + "/ListOrders": true,
+}
+
+// AuditInterceptor records every state changing unary call to l
+func AuditInterceptor(l *audit.Log) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if readOnly[info.FullMethod] {
+			return handler(ctx, req)
+		}
+
+		// recorded as received, before the handler fills in the request
+		payload, _ := json.Marshal(req)
+
+		ctx, clOrdIDs := audit.Track(ctx)
+		resp, err := handler(ctx, req)
+
+		rec := &audit.Record{
+			Kind:     audit.GRPC,
+			Method:   "grpc",
+			Path:     info.FullMethod,
+			Status:   int(status.Code(err)),
+			Payload:  string(payload),
+			ClOrdIDs: clOrdIDs(),
+		}
+		if user := auth.UserFromContext(ctx); user != nil {
+			rec.User = user.Username
+		}
+		if p, ok := peer.FromContext(ctx); ok {
+			rec.RemoteAddr = p.Addr.String()
+		}
+
+		if auditErr := l.Append(rec); auditErr != nil {
+			log.Printf("[ERROR] audit: %v\n", auditErr)
+		}
+
+		return resp, err
+	}
+}
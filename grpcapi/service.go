@@ -0,0 +1,172 @@
+// Package grpcapi is a gRPC api for order entry and streaming order and execution updates.
+//
+// This is a Go-only JSON-over-gRPC api, not a protobuf one. Messages are the json encoded types of
+// this package and of oms, exchanged with the "json" content subtype (content-type
+// application/grpc+json), and the service is described by ServiceDesc instead of a .proto file.
+// There are no stubs for other languages and no server reflection, so protoc generated clients and
+// tools such as grpcurl cannot call it. Go programs use NewOrderServiceClient; clients in other
+// languages must register a json codec of their own and build the messages from the REST api's
+// OpenAPI document.
+package grpcapi
+
+import (
+	"context"
+	"time"
+
+	"github.com/quickfixgo/enum"
+	"google.golang.org/grpc"
+
+	"github.com/quickfixgo/traderui/oms"
+)
+
+// ServiceName is the full name of the order service
+const ServiceName = "traderui.OrderService"
+
+// OrderRequest identifies an order
+type OrderRequest struct {
+	ID int `json:"id"`
+}
+
+// ReplaceOrderRequest amends an order, empty fields are left unchanged
+type ReplaceOrderRequest struct {
+	ID        int    `json:"id"`
+	Quantity  string `json:"quantity"`
+	Price     string `json:"price"`
+	StopPrice string `json:"stop_price"`
+}
+
+// ListOrdersRequest selects a page of orders. Zero values match any order.
+type ListOrdersRequest struct {
+	Status  []enum.OrdStatus `json:"status"`
+	Symbol  string           `json:"symbol"`
+	Account string           `json:"account"`
+	Session string           `json:"session_id"`
+	Side    enum.Side        `json:"side"`
+	Since   time.Time        `json:"since"`
+	Sort    string           `json:"sort"`
+	Limit   int              `json:"limit"`
+	Cursor  int              `json:"cursor"`
+}
+
+// ListOrdersResponse is a page of orders
+type ListOrdersResponse struct {
+	Orders []*oms.Order `json:"orders"`
+	// NextCursor is the cursor of the next page, 0 on the last page
+	NextCursor int `json:"next_cursor"`
+}
+
+// SubscribeRequest filters a stream of updates. Zero values match any update.
+type SubscribeRequest struct {
+	Symbol  string `json:"symbol"`
+	Session string `json:"session_id"`
+}
+
+// Matches is true if an update on symbol and session passes the filter
+func (r *SubscribeRequest) Matches(symbol, session string) bool {
+	return (r.Symbol == "" || r.Symbol == symbol) && (r.Session == "" || r.Session == session)
+}
+
+// OrderServiceServer is the server api of the order service
+type OrderServiceServer interface {
+	SubmitOrder(context.Context, *oms.Order) (*oms.Order, error)
+	CancelOrder(context.Context, *OrderRequest) (*oms.Order, error)
+	ReplaceOrder(context.Context, *ReplaceOrderRequest) (*oms.Order, error)
+	ListOrders(context.Context, *ListOrdersRequest) (*ListOrdersResponse, error)
+	SubscribeOrderEvents(*SubscribeRequest, OrderService_SubscribeOrderEventsServer) error
+	SubscribeExecutions(*SubscribeRequest, OrderService_SubscribeExecutionsServer) error
+}
+
+// OrderService_SubscribeOrderEventsServer streams order snapshots to the client
+type OrderService_SubscribeOrderEventsServer interface {
+	Send(*oms.Order) error
+	grpc.ServerStream
+}
+
+// OrderService_SubscribeExecutionsServer streams executions to the client
+type OrderService_SubscribeExecutionsServer interface {
+	Send(*oms.Execution) error
+	grpc.ServerStream
+}
+
+type streamServer[T any] struct {
+	grpc.ServerStream
+}
+
+func (s streamServer[T]) Send(m *T) error {
+	return s.ServerStream.SendMsg(m)
+}
+
+// RegisterOrderServiceServer registers srv with s
+func RegisterOrderServiceServer(s grpc.ServiceRegistrar, srv OrderServiceServer) {
+	s.RegisterService(&ServiceDesc, srv)
+}
+
+// unaryHandler adapts a typed unary method to the handler of a grpc.MethodDesc
+func unaryHandler[Req any, Resp any](name string, call func(OrderServiceServer, context.Context, *Req) (*Resp, error)) func(interface{}, context.Context, func(interface{}) error, grpc.UnaryServerInterceptor) (interface{}, error) {
+	return func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+		in := new(Req)
+		if err := dec(in); err != nil {
+			return nil, err
+		}
+
+		handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+			return call(srv.(OrderServiceServer), ctx, req.(*Req))
+		}
+
+		if interceptor == nil {
+			return handler(ctx, in)
+		}
+
+		info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/" + ServiceName + "/" + name}
+		return interceptor(ctx, in, info, handler)
+	}
+}
+
+// ServiceDesc describes the order service, as generated code would from a .proto. There is no
+// .proto, the messages are json.
+var ServiceDesc = grpc.ServiceDesc{
+	ServiceName: ServiceName,
+	HandlerType: (*OrderServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SubmitOrder",
+			Handler:    unaryHandler("SubmitOrder", OrderServiceServer.SubmitOrder),
+		},
+		{
+			MethodName: "CancelOrder",
+			Handler:    unaryHandler("CancelOrder", OrderServiceServer.CancelOrder),
+		},
+		{
+			MethodName: "ReplaceOrder",
+			Handler:    unaryHandler("ReplaceOrder", OrderServiceServer.ReplaceOrder),
+		},
+		{
+			MethodName: "ListOrders",
+			Handler:    unaryHandler("ListOrders", OrderServiceServer.ListOrders),
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName: "SubscribeOrderEvents",
+			Handler: func(srv interface{}, stream grpc.ServerStream) error {
+				in := new(SubscribeRequest)
+				if err := stream.RecvMsg(in); err != nil {
+					return err
+				}
+				return srv.(OrderServiceServer).SubscribeOrderEvents(in, streamServer[oms.Order]{stream})
+			},
+			ServerStreams: true,
+		},
+		{
+			StreamName: "SubscribeExecutions",
+			Handler: func(srv interface{}, stream grpc.ServerStream) error {
+				in := new(SubscribeRequest)
+				if err := stream.RecvMsg(in); err != nil {
+					return err
+				}
+				return srv.(OrderServiceServer).SubscribeExecutions(in, streamServer[oms.Execution]{stream})
+			},
+			ServerStreams: true,
+		},
+	},
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/quickfixgo/traderui/basic"
+	"github.com/quickfixgo/traderui/sender"
+)
+
+type sessionHealth struct {
+	basic.SessionStatus
+	Required bool                   `json:"required"`
+	Throttle *sender.ThrottleStatus `json:"throttle,omitempty"`
+}
+
+type healthStatus struct {
+	Status   string          `json:"status"`
+	Sessions []sessionHealth `json:"sessions"`
+}
+
+// parseRequiredSessions checks the comma separated session ids exist, all sessions are required
+// if empty
+func (c *tradeClient) parseRequiredSessions(sessions string) error {
+	c.requiredSessions = make(map[string]bool)
+	if sessions == "" {
+		for s := range c.SessionIDs {
+			c.requiredSessions[s] = true
+		}
+		return nil
+	}
+
+	for _, s := range strings.Split(sessions, ",") {
+		s = strings.TrimSpace(s)
+		if _, ok := c.SessionIDs[s]; !ok {
+			return fmt.Errorf("unknown required session %v", s)
+		}
+		c.requiredSessions[s] = true
+	}
+
+	return nil
+}
+
+// health returns the state of every session, ready if all required sessions are logged on
+func (c tradeClient) health() (status healthStatus, ready bool) {
+	ready = true
+	status.Sessions = make([]sessionHealth, 0)
+	for _, s := range c.fixApp.SessionStatuses() {
+		required := c.requiredSessions[s.Session]
+		if required && !s.LoggedOn {
+			ready = false
+		}
+		health := sessionHealth{SessionStatus: s, Required: required}
+		if c.throttle != nil {
+			if throttle, ok := c.throttle.Status(c.SessionIDs[s.Session]); ok {
+				health.Throttle = &throttle
+			}
+		}
+		status.Sessions = append(status.Sessions, health)
+	}
+
+	return status, ready
+}
+
+func writeHealthJSON(w http.ResponseWriter, code int, status healthStatus) {
+	outgoingJSON, err := json.Marshal(status)
+	if err != nil {
+		log.Printf("[ERROR] err = %+v\n", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	fmt.Fprint(w, string(outgoingJSON))
+}
+
+// healthz reports the process is alive
+func (c tradeClient) healthz(w http.ResponseWriter, r *http.Request) {
+	status, _ := c.health()
+	status.Status = "ok"
+	writeHealthJSON(w, http.StatusOK, status)
+}
+
+// readyz fails unless the required sessions are logged on
+func (c tradeClient) readyz(w http.ResponseWriter, r *http.Request) {
+	status, ready := c.health()
+	if !ready {
+		status.Status = "unavailable"
+		writeHealthJSON(w, http.StatusServiceUnavailable, status)
+		return
+	}
+
+	status.Status = "ok"
+	writeHealthJSON(w, http.StatusOK, status)
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/quickfixgo/quickfix"
+
+	"github.com/quickfixgo/traderui/fixlog"
+)
+
+// logConfig selects where FIX messages and session events are logged
+type logConfig struct {
+	// Outputs are comma separated: fancy, json and file
+	Outputs string
+	// JSONFile receives the json lines, stdout if empty
+	JSONFile string
+	// Heartbeats logs heartbeats and test requests
+	Heartbeats bool
+	// Include and Exclude are comma separated MsgTypes the fancy log is limited to or leaves out
+	Include, Exclude string
+	// SpecDir holds the quickfix spec files naming the fields of sessions without a DataDictionary
+	SpecDir string
+}
+
+// newLogFactory returns the factory teeing to each output and a function closing the files it
+// opened
+func (cfg logConfig) newLogFactory(settings *quickfix.Settings) (quickfix.LogFactory, func(), error) {
+	var factories []quickfix.LogFactory
+	closer := func() {}
+
+	for _, output := range strings.Split(cfg.Outputs, ",") {
+		switch strings.TrimSpace(output) {
+		case "fancy":
+			factories = append(factories, NewFancyLog(fixlog.NewDictionaries(settings, cfg.SpecDir), cfg.Include, cfg.Exclude))
+
+		case "json":
+			var w io.Writer = os.Stdout
+			if cfg.JSONFile != "" {
+				f, err := os.OpenFile(cfg.JSONFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+				if err != nil {
+					return nil, closer, err
+				}
+				w, closer = f, func() { f.Close() }
+			}
+			factories = append(factories, fixlog.NewJSONLogFactory(w))
+
+		case "file":
+			f, err := quickfix.NewFileLogFactory(settings)
+			if err != nil {
+				return nil, closer, fmt.Errorf("file log: %v", err)
+			}
+			factories = append(factories, f)
+
+		default:
+			return nil, closer, fmt.Errorf("unknown log output %q, expected fancy, json or file", output)
+		}
+	}
+
+	factory := fixlog.Tee(factories...)
+	if !cfg.Heartbeats {
+		factory = fixlog.Skip(factory, fixlog.Heartbeats...)
+	}
+
+	return factory, closer, nil
+}
@@ -1,76 +1,192 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
 	"path"
+	"sort"
 	"strconv"
-	"text/template"
+	"syscall"
+	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/quickfixgo/enum"
+	"github.com/quickfixgo/traderui/algo"
+	"github.com/quickfixgo/traderui/audit"
+	"github.com/quickfixgo/traderui/auth"
 	"github.com/quickfixgo/traderui/basic"
+	"github.com/quickfixgo/traderui/basket"
+	"github.com/quickfixgo/traderui/metrics"
 	"github.com/quickfixgo/traderui/oms"
+	"github.com/quickfixgo/traderui/paper"
+	"github.com/quickfixgo/traderui/rfq"
 	"github.com/quickfixgo/traderui/secmaster"
+	"github.com/quickfixgo/traderui/sender"
+	"github.com/shopspring/decimal"
 
 	"github.com/quickfixgo/quickfix"
+	"google.golang.org/grpc"
 )
 
 type fixFactory interface {
 	NewOrderSingle(ord oms.Order) (msg quickfix.Messagable, err error)
+	NewOrderMultileg(ord oms.Order) (msg quickfix.Messagable, err error)
 	OrderCancelRequest(ord oms.Order, clOrdID string) (msg quickfix.Messagable, err error)
+	OrderCancelReplaceRequest(ord oms.Order, clOrdID string) (msg quickfix.Messagable, err error)
+	NewOrderList(listID string, orders []oms.Order) (msg quickfix.Messagable, err error)
+	QuoteRequest(req rfq.Request) (msg quickfix.Messagable, err error)
 	SecurityDefinitionRequest(req secmaster.SecurityDefinitionRequest) (msg quickfix.Messagable, err error)
 }
 
 type tradeClient struct {
-	SessionIDs map[string]quickfix.SessionID
+	SessionIDs       map[string]quickfix.SessionID
+	DropCopySessions map[quickfix.SessionID]bool
+	// PaperSessions are filled by the paper engine, which only takes single orders
+	PaperSessions map[quickfix.SessionID]bool
 	fixFactory
 	*oms.OrderManager
+	algos   *algo.Scheduler
+	baskets *basket.Manager
+	rfqs    *rfq.Manager
+	// securities is the security master, orders are checked against it if validateSecurities
+	securities         *secmaster.Store
+	validateSecurities bool
+
+	authenticator *auth.Authenticator
+	auditLog      *audit.Log
+	metrics       *metrics.Metrics
+
+	views  *views
+	fixApp *basic.FIXApplication
+	// sender delivers messages to their session
+	sender sender.Sender
+	// throttle limits the orders of each session sent through sender
+	throttle *sender.Throttle
+	// requiredSessions must be logged on for the client to be ready
+	requiredSessions map[string]bool
 }
 
 func newTradeClient(factory fixFactory, idGen oms.ClOrdIDGenerator) *tradeClient {
 	tc := &tradeClient{
-		SessionIDs:   make(map[string]quickfix.SessionID),
-		fixFactory:   factory,
-		OrderManager: oms.NewOrderManager(idGen),
+		SessionIDs:       make(map[string]quickfix.SessionID),
+		DropCopySessions: make(map[quickfix.SessionID]bool),
+		PaperSessions:    make(map[quickfix.SessionID]bool),
+		fixFactory:       factory,
+		OrderManager:     oms.NewOrderManager(idGen),
+		baskets:          basket.NewManager(),
+		rfqs:             rfq.NewManager(),
+		securities:       secmaster.NewStore(),
+		metrics:          metrics.New(),
+		sender:           sender.QuickFIX,
 	}
+	tc.algos = algo.NewScheduler(tc.OrderManager, tc, algo.SystemClock{})
+	tc.algos.Validate = func(child *oms.Order) error { return tc.validateSecurity(child) }
 
 	return tc
 }
 
-func (c tradeClient) SessionsAsJSON() (string, error) {
+// sessions returns the sessions the user of the request may trade on, drop copy sessions excluded
+func (c tradeClient) sessions(r *http.Request) []string {
 	sessionIDs := make([]string, 0, len(c.SessionIDs))
 
-	for s := range c.SessionIDs {
-		sessionIDs = append(sessionIDs, s)
+	for s, sessionID := range c.SessionIDs {
+		if !c.DropCopySessions[sessionID] && auth.CanUseSession(r, s) {
+			sessionIDs = append(sessionIDs, s)
+		}
 	}
 
-	b, err := json.Marshal(sessionIDs)
+	sort.Strings(sessionIDs)
+	return sessionIDs
+}
+
+func (c tradeClient) SessionsAsJSON(r *http.Request) (string, error) {
+	b, err := json.Marshal(c.sessions(r))
 	return string(b), err
 }
 
-func (c tradeClient) OrdersAsJSON() (string, error) {
+// ordersAsJSON returns a page of the orders matching q the user of the request may see
+func (c tradeClient) ordersAsJSON(r *http.Request, q oms.OrderQuery) (string, int, error) {
 	c.RLock()
 	defer c.RUnlock()
 
-	b, err := json.Marshal(c.GetAll())
-	return string(b), err
+	q.Allow = func(order *oms.Order) bool { return auth.CanView(r, order.Session, order.Account) }
+	orders, next, err := c.QueryOrders(q)
+	if err != nil {
+		return "", 0, err
+	}
+
+	b, err := json.Marshal(orders)
+	return string(b), next, err
 }
 
-func (c tradeClient) ExecutionsAsJSON() (string, error) {
+// executionsAsJSON returns a page of the executions matching q the user of the request may see
+func (c tradeClient) executionsAsJSON(r *http.Request, q oms.ExecutionQuery) (string, int, error) {
 	c.RLock()
 	defer c.RUnlock()
 
-	b, err := json.Marshal(c.GetAllExecutions())
-	return string(b), err
+	q.Allow = func(exec *oms.Execution) bool { return auth.CanView(r, exec.Session, exec.Account) }
+	executions, next, err := c.QueryExecutions(q)
+	if err != nil {
+		return "", 0, err
+	}
+
+	b, err := json.Marshal(executions)
+	return string(b), next, err
+}
+
+func (c tradeClient) OrdersAsJSON(r *http.Request) (string, error) {
+	s, _, err := c.ordersAsJSON(r, oms.OrderQuery{})
+	return s, err
+}
+
+func (c tradeClient) ExecutionsAsJSON(r *http.Request) (string, error) {
+	s, _, err := c.executionsAsJSON(r, oms.ExecutionQuery{})
+	return s, err
+}
+
+// traderPage is the data of the trader view, scoped to the user of the request
+type traderPage struct {
+	tradeClient
+	r *http.Request
+}
+
+func (p traderPage) SessionsAsJSON() (string, error)   { return p.tradeClient.SessionsAsJSON(p.r) }
+func (p traderPage) OrdersAsJSON() (string, error)     { return p.tradeClient.OrdersAsJSON(p.r) }
+func (p traderPage) ExecutionsAsJSON() (string, error) { return p.tradeClient.ExecutionsAsJSON(p.r) }
+
+// authorize checks the user of the request may perform action on session and account, writing an
+// error if not
+func authorize(w http.ResponseWriter, r *http.Request, action auth.Action, session, account string) bool {
+	if err := auth.Authorize(r, action, session, account); err != nil {
+		log.Printf("[ERROR] %v\n", err)
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return false
+	}
+
+	return true
+}
+
+// AuthEnabled is true if users must log in
+func (c tradeClient) AuthEnabled() bool {
+	return c.authenticator != nil
+}
+
+func (c tradeClient) loginView(w http.ResponseWriter, r *http.Request) {
+	if err := c.views.execute(w, "login.html", c); err != nil {
+		log.Printf("[ERROR] err = %+v\n", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
 }
 
 func (c tradeClient) traderView(w http.ResponseWriter, r *http.Request) {
-	var templates = template.Must(template.New("traderui").ParseFiles("tmpl/index.html"))
-	if err := templates.ExecuteTemplate(w, "index.html", c); err != nil {
+	if err := c.views.execute(w, "index.html", traderPage{c, r}); err != nil {
 		log.Printf("[ERROR] err = %+v\n", err)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 	}
@@ -101,8 +217,8 @@ func (c tradeClient) getOrder(w http.ResponseWriter, r *http.Request) {
 	defer c.RUnlock()
 
 	order, err := c.fetchRequestedOrder(r)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusNotFound)
+	if err != nil || !auth.CanView(r, order.Session, order.Account) {
+		http.Error(w, "Order not found", http.StatusNotFound)
 		return
 	}
 
@@ -126,8 +242,8 @@ func (c tradeClient) getExecution(w http.ResponseWriter, r *http.Request) {
 	defer c.RUnlock()
 
 	exec, err := c.fetchRequestedExecution(r)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusNotFound)
+	if err != nil || !auth.CanView(r, exec.Session, exec.Account) {
+		http.Error(w, "Execution not found", http.StatusNotFound)
 		return
 	}
 
@@ -143,34 +259,84 @@ func (c tradeClient) getExecution(w http.ResponseWriter, r *http.Request) {
 }
 
 func (c tradeClient) deleteOrder(w http.ResponseWriter, r *http.Request) {
-	c.Lock()
-	defer c.Unlock()
-
+	c.RLock()
 	order, err := c.fetchRequestedOrder(r)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusNotFound)
+	c.RUnlock()
+	if err != nil || !auth.CanView(r, order.Session, order.Account) {
+		http.Error(w, "Order not found", http.StatusNotFound)
 		return
 	}
 
-	clOrdID := c.AssignNextClOrdID(order)
-	msg, err := c.OrderCancelRequest(*order, clOrdID)
-	if err != nil {
+	if !authorize(w, r, auth.ActionCancel, order.Session, order.Account) {
+		return
+	}
+
+	if !auth.CanModify(r, order.User) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	audit.AddClOrdIDs(r, order.ClOrdID)
+
+	if err = c.cancel(order); err != nil {
 		log.Printf("[ERROR] err = %+v\n", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), sendStatus(err, http.StatusInternalServerError))
+		return
+	}
+
+	c.RLock()
+	defer c.RUnlock()
+	c.writeOrderJSON(w, order)
+}
+
+// orderAmendment is the requested change of an order, empty fields are left unchanged
+type orderAmendment struct {
+	Quantity  string `json:"quantity"`
+	Price     string `json:"price"`
+	StopPrice string `json:"stop_price"`
+}
+
+func (c tradeClient) amendOrder(w http.ResponseWriter, r *http.Request) {
+	var amendment orderAmendment
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&amendment); err != nil {
+		log.Printf("[ERROR] %v\n", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	c.RLock()
+	order, err := c.fetchRequestedOrder(r)
+	c.RUnlock()
+	if err != nil || !auth.CanView(r, order.Session, order.Account) {
+		http.Error(w, "Order not found", http.StatusNotFound)
+		return
+	}
+
+	if !authorize(w, r, auth.ActionAmend, order.Session, order.Account) {
+		return
+	}
+
+	if !auth.CanModify(r, order.User) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
 		return
 	}
 
-	err = quickfix.SendToTarget(msg, order.SessionID)
+	clOrdID, err := c.ReplaceOrder(order, amendment)
 	if err != nil {
-		log.Printf("[ERROR] err = %+v\n", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		log.Printf("[ERROR] %v\n", err)
+		http.Error(w, err.Error(), sendStatus(err, http.StatusBadRequest))
+		return
 	}
+	audit.AddClOrdIDs(r, order.ClOrdID, clOrdID)
 
+	c.RLock()
+	defer c.RUnlock()
 	c.writeOrderJSON(w, order)
 }
 
-func (c tradeClient) getOrders(w http.ResponseWriter, r *http.Request) {
-	outgoingJSON, err := c.OrdersAsJSON()
+func (c tradeClient) getSessions(w http.ResponseWriter, r *http.Request) {
+	outgoingJSON, err := c.SessionsAsJSON(r)
 	if err != nil {
 		log.Printf("[ERROR] err = %+v\n", err)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -181,16 +347,36 @@ func (c tradeClient) getOrders(w http.ResponseWriter, r *http.Request) {
 	fmt.Fprint(w, outgoingJSON)
 }
 
+func (c tradeClient) getOrders(w http.ResponseWriter, r *http.Request) {
+	q, err := parseOrderQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	outgoingJSON, next, err := c.ordersAsJSON(r, q)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	writePageJSON(w, outgoingJSON, next)
+}
+
 func (c tradeClient) getExecutions(w http.ResponseWriter, r *http.Request) {
-	outgoingJSON, err := c.ExecutionsAsJSON()
+	q, err := parseExecutionQuery(r)
 	if err != nil {
-		log.Printf("[ERROR] err = %+v\n", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	fmt.Fprint(w, outgoingJSON)
+	outgoingJSON, next, err := c.executionsAsJSON(r, q)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	writePageJSON(w, outgoingJSON, next)
 }
 
 func (c tradeClient) newSecurityDefintionRequest(w http.ResponseWriter, r *http.Request) {
@@ -205,6 +391,12 @@ func (c tradeClient) newSecurityDefintionRequest(w http.ResponseWriter, r *http.
 
 	log.Printf("secDefRequest = %+v\n", secDefRequest)
 
+	if err = auth.AuthorizeSession(r, auth.ActionSecDef, secDefRequest.Session); err != nil {
+		log.Printf("[ERROR] %v\n", err)
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
 	if sessionID, ok := c.SessionIDs[secDefRequest.Session]; ok {
 		secDefRequest.SessionID = sessionID
 	} else {
@@ -220,7 +412,7 @@ func (c tradeClient) newSecurityDefintionRequest(w http.ResponseWriter, r *http.
 		return
 	}
 
-	err = quickfix.SendToTarget(msg, secDefRequest.SessionID)
+	err = c.sender.Send(msg, secDefRequest.SessionID)
 
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -237,39 +429,307 @@ func (c tradeClient) newOrder(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if sessionID, ok := c.SessionIDs[order.Session]; ok {
-		order.SessionID = sessionID
-	} else {
-		log.Println("[ERROR] Invalid SessionID")
-		http.Error(w, "Invalid SessionID", http.StatusBadRequest)
+	c.submitOrder(w, r, &order)
+}
+
+// submitOrder authorizes, validates and sends an order entered by the user of the request
+func (c tradeClient) submitOrder(w http.ResponseWriter, r *http.Request, order *oms.Order) {
+	if !authorize(w, r, auth.ActionNew, order.Session, order.Account) {
 		return
 	}
 
-	if err = order.Init(); err != nil {
+	order.User = auth.Username(r)
+	if err := c.validateOrder(order); err != nil {
 		log.Printf("[ERROR] %v\n", err)
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
+	err := c.SendOrder(order)
+	audit.AddClOrdIDs(r, order.ClOrdID)
+	if err != nil {
+		log.Printf("[ERROR] %v\n", err)
+		http.Error(w, err.Error(), sendStatus(err, http.StatusInternalServerError))
+		return
+	}
+
+	c.RLock()
+	defer c.RUnlock()
+	c.writeOrderJSON(w, order)
+}
+
+// validateOrder resolves the order's session and initializes it from user input, clearing the
+// fields the server owns. The User must be set by the caller.
+func (c tradeClient) validateOrder(order *oms.Order) error {
+	order.ResetServerFields()
+
+	sessionID, ok := c.SessionIDs[order.Session]
+	if !ok {
+		c.metrics.OrderRejected("unknown", metrics.RejectedValidation)
+		return errors.New("Invalid SessionID")
+	}
+
+	if c.DropCopySessions[sessionID] {
+		c.metrics.OrderRejected(order.Session, metrics.RejectedValidation)
+		return errors.New("Cannot trade on a drop copy session")
+	}
+	order.SessionID = sessionID
+
+	if err := order.Init(); err != nil {
+		c.metrics.OrderRejected(order.Session, metrics.RejectedValidation)
+		return err
+	}
+
+	if len(order.Legs) > 0 && c.PaperSessions[sessionID] {
+		c.metrics.OrderRejected(order.Session, metrics.RejectedValidation)
+		return errors.New("Cannot send multileg orders to a paper session")
+	}
+
+	if err := c.validateSecurity(order); err != nil {
+		c.metrics.OrderRejected(order.Session, metrics.RejectedValidation)
+		return err
+	}
+
+	return nil
+}
+
+// validateSecurity checks the initialized order against the security master, if enabled
+func (c tradeClient) validateSecurity(order *oms.Order) error {
+	if !c.validateSecurities {
+		return nil
+	}
+
+	c.securities.RLock()
+	defer c.securities.RUnlock()
+	return c.securities.Validate(order)
+}
+
+// SendOrder saves the order and sends it to the order's session as a NewOrderSingle, or as a
+// NewOrderMultileg if the order has legs
+func (c tradeClient) SendOrder(order *oms.Order) error {
 	c.Lock()
-	_ = c.OrderManager.Save(&order)
+	_ = c.OrderManager.Save(order)
+	var msg quickfix.Messagable
+	var err error
+	if len(order.Legs) > 0 {
+		msg, err = c.NewOrderMultileg(*order)
+	} else {
+		msg, err = c.NewOrderSingle(*order)
+	}
 	c.Unlock()
+	if err == nil {
+		err = c.sender.Send(msg, order.SessionID)
+	}
+	if err != nil {
+		c.rejectUnsent(order)
+		return err
+	}
 
-	msg, err := c.NewOrderSingle(order)
+	c.metrics.OrderSubmitted(order.Session)
+	return nil
+}
+
+// rejectUnsent marks saved orders that could not be sent as rejected, so they do not show as
+// working
+func (c tradeClient) rejectUnsent(orders ...*oms.Order) {
+	c.Lock()
+	defer c.Unlock()
+
+	for _, order := range orders {
+		order.OrdStatus = enum.OrdStatus_REJECTED
+		order.Closed, order.Open = "0", "0"
+		c.OrderUpdated(order)
+		c.metrics.OrderRejected(order.Session, metrics.RejectedValidation)
+	}
+}
+
+// SendOrderList saves the orders and sends them to their session as a single NewOrderList
+func (c tradeClient) SendOrderList(listID string, orders []*oms.Order) error {
+	c.Lock()
+	list := make([]oms.Order, 0, len(orders))
+	for _, order := range orders {
+		_ = c.OrderManager.Save(order)
+		list = append(list, *order)
+	}
+	msg, err := c.NewOrderList(listID, list)
+	c.Unlock()
+	if err == nil {
+		err = c.sender.Send(msg, orders[0].SessionID)
+	}
 	if err != nil {
-		log.Printf("[ERROR] %v\n", err)
-		http.Error(w, err.Error(), http.StatusBadRequest)
-		return
+		c.rejectUnsent(orders...)
+		return err
 	}
 
-	err = quickfix.SendToTarget(msg, order.SessionID)
+	for _, order := range orders {
+		c.metrics.OrderSubmitted(order.Session)
+	}
+	return nil
+}
+
+// cancel cancels the algo working the order if it is an algo parent, or sends an
+// OrderCancelRequest for the order otherwise
+func (c tradeClient) cancel(order *oms.Order) error {
+	c.algos.Lock()
+	algo, err := c.algos.GetByParentID(order.ID)
+	c.algos.Unlock()
+	if err == nil {
+		return c.algos.Cancel(algo.ID)
+	}
 
+	return c.CancelOrder(order)
+}
+
+// CancelOrder sends an OrderCancelRequest for the order to the order's session
+func (c tradeClient) CancelOrder(order *oms.Order) error {
+	if order.External {
+		return errors.New("Cannot cancel an order placed outside of this client")
+	}
+
+	c.Lock()
+	clOrdID := c.AssignNextClOrdID(order)
+	msg, err := c.OrderCancelRequest(*order, clOrdID)
+	c.Unlock()
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return err
+	}
+
+	return c.sender.Send(msg, order.SessionID)
+}
+
+// ReplaceOrder sends an OrderCancelReplaceRequest amending the order to the order's session,
+// returning the ClOrdID of the request. The order is updated once the replace is accepted.
+func (c tradeClient) ReplaceOrder(order *oms.Order, amendment orderAmendment) (string, error) {
+	if order.External {
+		return "", errors.New("Cannot amend an order placed outside of this client")
+	}
+
+	if len(order.Legs) > 0 {
+		return "", errors.New("Cannot amend a multileg order")
+	}
+
+	c.Lock()
+	amended := *order
+	c.Unlock()
+
+	switch amended.OrdStatus {
+	case enum.OrdStatus_FILLED, enum.OrdStatus_CANCELED, enum.OrdStatus_REJECTED, enum.OrdStatus_DONE_FOR_DAY, enum.OrdStatus_EXPIRED:
+		return "", fmt.Errorf("Cannot amend an order with status %v", amended.OrdStatus)
+	}
+
+	if amendment.Quantity != "" {
+		amended.Quantity = amendment.Quantity
 	}
+	if amendment.Price != "" {
+		amended.Price = amendment.Price
+	}
+	if amendment.StopPrice != "" {
+		amended.StopPrice = amendment.StopPrice
+	}
+	if err := amended.Init(); err != nil {
+		return "", err
+	}
+	if closed, _ := decimal.NewFromString(amended.Closed); amended.QuantityDecimal.LessThanOrEqual(closed) {
+		return "", fmt.Errorf("Cannot amend the quantity to %v, %v already filled", amended.Quantity, closed)
+	}
+	if err := c.validateSecurity(&amended); err != nil {
+		c.metrics.OrderRejected(order.Session, metrics.RejectedValidation)
+		return "", err
+	}
+
+	c.Lock()
+	clOrdID := c.AssignNextClOrdID(order)
+	msg, err := c.OrderCancelReplaceRequest(amended, clOrdID)
+	c.Unlock()
+	if err != nil {
+		return "", err
+	}
+
+	return clOrdID, c.sender.Send(msg, order.SessionID)
+}
+
+// routes registers the api and ui handlers on router
+func (c *tradeClient) routes(router *mux.Router) {
+	router.Handle("/metrics", c.metrics).Methods("GET")
+	router.HandleFunc("/openapi.json", serveOpenAPI).Methods("GET")
+	router.HandleFunc("/healthz", c.healthz).Methods("GET")
+	router.HandleFunc("/readyz", c.readyz).Methods("GET")
+	router.HandleFunc("/sessions", c.getSessions).Methods("GET")
+
+	router.HandleFunc("/orders", c.newOrder).Methods("POST")
+	router.HandleFunc("/orders", c.getOrders).Methods("GET")
+	router.HandleFunc("/orders/{id:[0-9]+}", c.getOrder).Methods("GET")
+	router.HandleFunc("/orders/{id:[0-9]+}", c.deleteOrder).Methods("DELETE")
+	router.HandleFunc("/orders/{id:[0-9]+}", c.amendOrder).Methods("PATCH")
+
+	router.HandleFunc("/templates", c.newTemplate).Methods("POST")
+	router.HandleFunc("/templates", c.getTemplates).Methods("GET")
+	router.HandleFunc("/templates/{id:[0-9]+}", c.getTemplate).Methods("GET")
+	router.HandleFunc("/templates/{id:[0-9]+}", c.updateTemplate).Methods("PUT")
+	router.HandleFunc("/templates/{id:[0-9]+}", c.deleteTemplate).Methods("DELETE")
+	router.HandleFunc("/templates/{id:[0-9]+}/submit", c.submitTemplate).Methods("POST")
+
+	router.HandleFunc("/executions", c.getExecutions).Methods("GET")
+	router.HandleFunc("/executions/{id:[0-9]+}", c.getExecution).Methods("GET")
+
+	router.HandleFunc("/algos", c.newAlgo).Methods("POST")
+	router.HandleFunc("/algos", c.getAlgos).Methods("GET")
+	router.HandleFunc("/algos/{id:[0-9]+}", c.getAlgo).Methods("GET")
+	router.HandleFunc("/algos/{id:[0-9]+}", c.deleteAlgo).Methods("DELETE")
+	router.HandleFunc("/algos/{id:[0-9]+}/pause", c.pauseAlgo).Methods("POST")
+	router.HandleFunc("/algos/{id:[0-9]+}/resume", c.resumeAlgo).Methods("POST")
+
+	router.HandleFunc("/baskets", c.newBasket).Methods("POST")
+	router.HandleFunc("/baskets", c.getBaskets).Methods("GET")
+	router.HandleFunc("/baskets/{id:[0-9]+}", c.getBasket).Methods("GET")
+	router.HandleFunc("/baskets/{id:[0-9]+}", c.deleteBasket).Methods("DELETE")
+	router.HandleFunc("/baskets/{id:[0-9]+}/confirm", c.confirmBasket).Methods("POST")
+
+	router.HandleFunc("/rfqs", c.newRFQ).Methods("POST")
+	router.HandleFunc("/rfqs", c.getRFQs).Methods("GET")
+	router.HandleFunc("/rfqs/{id:[0-9]+}", c.getRFQ).Methods("GET")
+	router.HandleFunc("/rfqs/{id:[0-9]+}/quotes/{quote_id}/hit", c.hitQuote).Methods("POST")
+
+	router.HandleFunc("/securities", c.getSecurities).Methods("GET")
+	router.HandleFunc("/securities/{symbol}", c.getSecurity).Methods("GET")
+	router.HandleFunc("/securitydefinitionrequest", c.newSecurityDefintionRequest).Methods("POST")
+
+	router.PathPrefix("/assets/").Handler(http.StripPrefix("/assets/", c.views.assets()))
+	router.HandleFunc("/", c.traderView)
 }
 
 func main() {
+	usersFileName := flag.String("users", "", "json file of users allowed to log in, authentication is disabled if empty")
+	auditFileName := flag.String("audit", "", "file of the audit log, auditing is disabled if empty")
+	auditKeyFileName := flag.String("audit-key", "", "file of the key the audit log records are hashed with, required with -audit")
+	secmasterFileName := flag.String("secmaster", "", "csv or json file of securities orders are validated against, orders are not validated against reference data if empty")
+	recordFileName := flag.String("record", "", "file recording inbound application messages for replay, recording is disabled if empty")
+
+	var srvCfg serverConfig
+	flag.StringVar(&srvCfg.Addr, "addr", ":8080", "address the ui listens on")
+	grpcAddr := flag.String("grpc-addr", "", "address the gRPC api listens on, the gRPC api is disabled if empty")
+	flag.StringVar(&srvCfg.CertFile, "tls-cert", "", "certificate file, serves https if set")
+	flag.StringVar(&srvCfg.KeyFile, "tls-key", "", "private key file of the certificate")
+	flag.StringVar(&srvCfg.ClientCAFile, "tls-client-ca", "", "CA certificates file, requires and verifies client certificates if set")
+	flag.DurationVar(&srvCfg.ReadTimeout, "read-timeout", 15*time.Second, "maximum duration for reading a request")
+	flag.DurationVar(&srvCfg.WriteTimeout, "write-timeout", 15*time.Second, "maximum duration for writing a response")
+	flag.DurationVar(&srvCfg.IdleTimeout, "idle-timeout", 60*time.Second, "maximum time to wait for the next request on a keep-alive connection")
+	requiredSessions := flag.String("required-sessions", "", "comma separated sessions that must be logged on for /readyz, all sessions if empty")
+	dev := flag.Bool("dev", false, "serve templates and assets from the working directory instead of the binary")
+	var logCfg logConfig
+	flag.StringVar(&logCfg.Outputs, "log", "fancy", "comma separated FIX logs: fancy (coloured tables on stdout), json (one object per line) and file (quickfix file log under FileLogPath)")
+	flag.StringVar(&logCfg.JSONFile, "log-json-file", "", "file of the json log, stdout if empty")
+	flag.BoolVar(&logCfg.Heartbeats, "log-heartbeats", true, "log heartbeats and test requests")
+	flag.StringVar(&logCfg.Include, "log-include-msgtypes", "", "comma separated MsgTypes the fancy log is limited to, e.g. D,8,9")
+	flag.StringVar(&logCfg.Exclude, "log-exclude-msgtypes", "", "comma separated MsgTypes the fancy log leaves out")
+	flag.StringVar(&logCfg.SpecDir, "fix-spec-dir", "", "directory of quickfix spec files (FIX42.xml, ...) naming fields in the fancy log for sessions without DataDictionary settings")
+	var sendCfg senderConfig
+	flag.Float64Var(&sendCfg.Throttle.Rate, "send-rate", 0, "orders, cancels and replaces a second each session may send, unlimited if 0; overridden by the ThrottleRate session setting")
+	flag.IntVar(&sendCfg.Throttle.Burst, "send-burst", 10, "orders a session may send at once when throttled; overridden by ThrottleBurst")
+	sendPolicy := flag.String("send-policy", "reject", "what to do with orders over the rate: reject (429) or queue; overridden by ThrottlePolicy")
+	flag.IntVar(&sendCfg.Throttle.Backlog, "send-backlog", 100, "orders a session queues at most before rejecting them; overridden by ThrottleBacklog")
+	flag.BoolVar(&sendCfg.DryRun, "dry-run", false, "log messages instead of sending them")
+	shutdownTimeout := flag.Duration("shutdown-timeout", 10*time.Second, "maximum time to wait for requests to drain on shutdown")
 	flag.Parse()
 
 	cfgFileName := path.Join("config", "tradeclient.cfg")
@@ -289,39 +749,245 @@ func main() {
 		return
 	}
 
-	logFactory := NewFancyLog()
+	logFactory, closeLogs, err := logCfg.newLogFactory(appSettings)
+	if err != nil {
+		fmt.Println("Error creating logs,", err)
+		return
+	}
+	defer closeLogs()
 
-	var fixApp quickfix.Application
 	app := newTradeClient(basic.FIXFactory{}, new(basic.ClOrdIDGenerator))
-	fixApp = &basic.FIXApplication{
-		SessionIDs:   app.SessionIDs,
-		OrderManager: app.OrderManager,
+	if app.views, err = newViews(*dev); err != nil {
+		fmt.Println("Error reading templates,", err)
+		return
+	}
+
+	// users, audit log and securities are read before the sessions start, a failure exits without logons
+	if *auditFileName != "" {
+		key, err := audit.LoadKey(*auditKeyFileName)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if app.auditLog, err = audit.Open(*auditFileName, key); err != nil {
+			log.Fatal(err)
+		}
+		defer func() {
+			log.Printf("audit log %v closed at %v\n", *auditFileName, app.auditLog.Head())
+			app.auditLog.Close()
+		}()
+
+		logFactory = app.auditLog.NewLogFactory(logFactory)
+	} else {
+		log.Println("[WARN] no audit file given, auditing is disabled")
+	}
+
+	if *usersFileName != "" {
+		users, err := auth.LoadUsers(*usersFileName)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		app.authenticator = auth.NewAuthenticator(users)
+		app.authenticator.Public = append(app.authenticator.Public, "/metrics", "/healthz", "/readyz", "/openapi.json")
 	}
 
-	initiator, err := quickfix.NewInitiator(fixApp, quickfix.NewMemoryStoreFactory(), appSettings, logFactory)
+	if *secmasterFileName != "" {
+		if err = app.securities.Load(*secmasterFileName); err != nil {
+			log.Fatal(err)
+		}
+		app.validateSecurities = true
+		log.Printf("loaded %v securities from %v\n", app.securities.Len(), *secmasterFileName)
+	}
+
+	if sendCfg.Throttle.Policy, err = sender.ParsePolicy(*sendPolicy); err != nil {
+		log.Fatal(err)
+	}
+	if err = sendCfg.readSessionLimits(appSettings.SessionSettings()); err != nil {
+		fmt.Println("Error reading cfg,", err)
+		return
+	}
+
+	// paper sessions are simulated in process, the initiator only connects the others
+	initiatorSettings := quickfix.NewSettings()
+	*initiatorSettings.GlobalSettings() = *appSettings.GlobalSettings()
+	paperPrices := make(map[quickfix.SessionID]decimal.Decimal)
+	for sessionID, settings := range appSettings.SessionSettings() {
+		if settings.HasSetting("DropCopy") {
+			if app.DropCopySessions[sessionID], err = settings.BoolSetting("DropCopy"); err != nil {
+				fmt.Println("Error reading cfg,", err)
+				return
+			}
+		}
+
+		var paper bool
+		if settings.HasSetting("Paper") {
+			if paper, err = settings.BoolSetting("Paper"); err != nil {
+				fmt.Println("Error reading cfg,", err)
+				return
+			}
+		}
+
+		switch {
+		case paper && app.DropCopySessions[sessionID]:
+			fmt.Printf("Error reading cfg, %v cannot be both a paper and a drop copy session\n", sessionID)
+			return
+		case paper:
+			app.PaperSessions[sessionID] = true
+			if settings.HasSetting("PaperReferencePrice") {
+				price, _ := settings.Setting("PaperReferencePrice")
+				if paperPrices[sessionID], err = decimal.NewFromString(price); err != nil {
+					fmt.Printf("Error reading cfg, invalid PaperReferencePrice %v of %v\n", price, sessionID)
+					return
+				}
+			}
+		default:
+			if _, err = initiatorSettings.AddSession(settings); err != nil {
+				fmt.Println("Error reading cfg,", err)
+				return
+			}
+		}
+	}
+
+	app.fixApp = &basic.FIXApplication{
+		SessionIDs:       app.SessionIDs,
+		OrderManager:     app.OrderManager,
+		RFQs:             app.rfqs,
+		Securities:       app.securities,
+		DropCopySessions: app.DropCopySessions,
+		Metrics:          app.metrics,
+	}
+
+	var fixApp quickfix.Application = app.fixApp
+	if *recordFileName != "" {
+		f, err := os.OpenFile(*recordFileName, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+
+		if fixApp, err = basic.NewRecorder(app.fixApp, f); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	initiator, err := quickfix.NewInitiator(fixApp, quickfix.NewMemoryStoreFactory(), initiatorSettings, logFactory)
 	if err != nil {
 		log.Fatalf("Unable to create Initiator: %s\n", err)
 	}
 
+	if len(app.PaperSessions) > 0 {
+		engine := paper.NewEngine(fixApp)
+		engine.LogFactory = logFactory
+		engine.ReferencePrice = func(sessionID quickfix.SessionID, symbol string) (decimal.Decimal, bool) {
+			app.securities.RLock()
+			defer app.securities.RUnlock()
+			if sec, ok := app.securities.Get(symbol); ok && sec.ReferencePrice.IsPositive() {
+				return sec.ReferencePrice, true
+			}
+
+			px, ok := paperPrices[sessionID]
+			return px, ok
+		}
+		sendCfg.Routes = make(map[quickfix.SessionID]sender.Sender)
+		for sessionID := range app.PaperSessions {
+			if err = engine.Logon(sessionID); err != nil {
+				log.Fatalf("Unable to log on paper session %v: %s\n", sessionID, err)
+			}
+			sendCfg.Routes[sessionID] = engine
+			log.Printf("[WARN] %v is a paper session, orders are filled by the paper engine\n", sessionID)
+		}
+	}
+	app.sender, app.throttle = sendCfg.newSender(app)
+
+	if err = app.parseRequiredSessions(*requiredSessions); err != nil {
+		log.Fatal(err)
+	}
+
 	if err = initiator.Start(); err != nil {
 		log.Fatal(err)
 	}
+	// runs after the http server has drained, logging out of the FIX sessions
 	defer initiator.Stop()
 
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go app.algos.Run(ctx, time.Second)
+
 	router := mux.NewRouter().StrictSlash(true)
+	router.Use(app.metrics.Middleware)
+
+	// requests are audited before authentication, so refused requests are recorded with their status
+	if app.auditLog != nil {
+		router.Use(app.auditLog.Middleware("/login"))
+		router.HandleFunc("/audit", app.getAudit).Methods("GET")
+	}
 
-	router.HandleFunc("/orders", app.newOrder).Methods("POST")
-	router.HandleFunc("/orders", app.getOrders).Methods("GET")
-	router.HandleFunc("/orders/{id:[0-9]+}", app.getOrder).Methods("GET")
-	router.HandleFunc("/orders/{id:[0-9]+}", app.deleteOrder).Methods("DELETE")
+	if app.authenticator != nil {
+		router.Use(app.authenticator.Middleware)
+		if app.auditLog != nil {
+			router.Use(audit.RecordUser)
+		}
+		router.HandleFunc("/login", app.loginView).Methods("GET")
+		router.HandleFunc("/login", app.authenticator.HandleLogin).Methods("POST")
+		router.HandleFunc("/logout", app.authenticator.HandleLogout).Methods("POST")
+	} else {
+		log.Println("[WARN] no users file given, authentication is disabled")
+	}
 
-	router.HandleFunc("/executions", app.getExecutions).Methods("GET")
-	router.HandleFunc("/executions/{id:[0-9]+}", app.getExecution).Methods("GET")
+	app.routes(router)
 
-	router.HandleFunc("/securitydefinitionrequest", app.newSecurityDefintionRequest).Methods("POST")
+	server, err := srvCfg.newServer(router)
+	if err != nil {
+		log.Printf("[ERROR] %v\n", err)
+		return
+	}
 
-	router.PathPrefix("/assets/").Handler(http.StripPrefix("/assets/", http.FileServer(http.Dir("assets"))))
-	router.HandleFunc("/", app.traderView)
+	errs := make(chan error, 2)
+	go func() {
+		errs <- srvCfg.serve(server)
+	}()
+	log.Printf("serving ui on %v, tls %v\n", srvCfg.Addr, srvCfg.TLS())
+
+	var grpcServer *grpc.Server
+	if *grpcAddr != "" {
+		if grpcServer, err = app.newGRPCServer(ctx, srvCfg); err != nil {
+			log.Printf("[ERROR] %v\n", err)
+			return
+		}
+
+		go func() {
+			errs <- serveGRPC(grpcServer, *grpcAddr)
+		}()
+		log.Printf("serving grpc on %v, tls %v\n", *grpcAddr, srvCfg.TLS())
+	}
 
-	log.Fatal(http.ListenAndServe(":8080", router))
+	select {
+	case <-ctx.Done():
+		log.Println("shutting down")
+	case err = <-errs:
+		log.Printf("[ERROR] %v\n", err)
+	}
+	stop()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), *shutdownTimeout)
+	defer cancel()
+
+	if err = server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("[ERROR] http shutdown: %v\n", err)
+	}
+
+	if grpcServer != nil {
+		stopped := make(chan struct{})
+		go func() {
+			grpcServer.GracefulStop()
+			close(stopped)
+		}()
+
+		select {
+		case <-stopped:
+		case <-shutdownCtx.Done():
+			grpcServer.Stop()
+		}
+	}
 }
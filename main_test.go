@@ -0,0 +1,744 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/quickfixgo/enum"
+	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/tag"
+	"github.com/shopspring/decimal"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/quickfixgo/traderui/algo"
+	"github.com/quickfixgo/traderui/auth"
+	"github.com/quickfixgo/traderui/basic"
+	"github.com/quickfixgo/traderui/oms"
+	"github.com/quickfixgo/traderui/rfq"
+	"github.com/quickfixgo/traderui/secmaster"
+	"github.com/quickfixgo/traderui/sender"
+)
+
+var testSessionID = quickfix.SessionID{BeginString: quickfix.BeginStringFIX42, SenderCompID: "TW", TargetCompID: "ISLD"}
+
+// fakeFactory builds bare messages of the requested MsgType carrying the ClOrdIDs, failing for
+// the symbol FAIL
+type fakeFactory struct{}
+
+func fakeMessage(msgType enum.MsgType, symbol string, fields map[quickfix.Tag]string) (quickfix.Messagable, error) {
+	if symbol == "FAIL" {
+		return nil, errors.New("cannot build message")
+	}
+
+	msg := quickfix.NewMessage()
+	msg.Header.SetString(tag.MsgType, string(msgType))
+	for tg, value := range fields {
+		msg.Body.SetString(tg, value)
+	}
+
+	return msg, nil
+}
+
+func (fakeFactory) NewOrderSingle(ord oms.Order) (quickfix.Messagable, error) {
+	return fakeMessage(enum.MsgType_ORDER_SINGLE, ord.Symbol, map[quickfix.Tag]string{tag.ClOrdID: ord.ClOrdID})
+}
+
+func (fakeFactory) NewOrderMultileg(ord oms.Order) (quickfix.Messagable, error) {
+	return fakeMessage(enum.MsgType_NEW_ORDER_MULTILEG, ord.Symbol, map[quickfix.Tag]string{tag.ClOrdID: ord.ClOrdID})
+}
+
+func (fakeFactory) OrderCancelRequest(ord oms.Order, clOrdID string) (quickfix.Messagable, error) {
+	return fakeMessage(enum.MsgType_ORDER_CANCEL_REQUEST, ord.Symbol, map[quickfix.Tag]string{tag.ClOrdID: clOrdID, tag.OrigClOrdID: ord.ClOrdID})
+}
+
+func (fakeFactory) OrderCancelReplaceRequest(ord oms.Order, clOrdID string) (quickfix.Messagable, error) {
+	return fakeMessage(enum.MsgType_ORDER_CANCEL_REPLACE_REQUEST, ord.Symbol, map[quickfix.Tag]string{
+		tag.ClOrdID: clOrdID, tag.OrigClOrdID: ord.ClOrdID, tag.OrderQty: ord.Quantity, tag.Price: ord.Price,
+	})
+}
+
+func (fakeFactory) NewOrderList(listID string, orders []oms.Order) (quickfix.Messagable, error) {
+	return fakeMessage(enum.MsgType_ORDER_LIST, orders[0].Symbol, map[quickfix.Tag]string{tag.ListID: listID})
+}
+
+func (fakeFactory) QuoteRequest(req rfq.Request) (quickfix.Messagable, error) {
+	return fakeMessage(enum.MsgType_QUOTE_REQUEST, req.Symbol, map[quickfix.Tag]string{tag.QuoteReqID: req.QuoteReqID})
+}
+
+func (fakeFactory) SecurityDefinitionRequest(req secmaster.SecurityDefinitionRequest) (quickfix.Messagable, error) {
+	return fakeMessage(enum.MsgType_SECURITY_DEFINITION_REQUEST, req.Symbol, nil)
+}
+
+// captureSender records the messages sent instead of sending them, failing with err if set
+type captureSender struct {
+	mu   sync.Mutex
+	msgs []*quickfix.Message
+	err  error
+}
+
+func (s *captureSender) Send(m quickfix.Messagable, sessionID quickfix.SessionID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.err != nil {
+		return s.err
+	}
+	s.msgs = append(s.msgs, m.ToMessage())
+	return nil
+}
+
+// msgTypes returns the MsgTypes sent so far
+func (s *captureSender) msgTypes() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	msgTypes := make([]string, 0, len(s.msgs))
+	for _, msg := range s.msgs {
+		msgType, _ := msg.MsgType()
+		msgTypes = append(msgTypes, msgType)
+	}
+
+	return msgTypes
+}
+
+func newTestClient(t *testing.T) (*tradeClient, *captureSender, http.Handler) {
+	t.Helper()
+
+	c := newTradeClient(fakeFactory{}, new(basic.ClOrdIDGenerator))
+	sender := new(captureSender)
+	c.sender = sender
+
+	c.fixApp = &basic.FIXApplication{
+		SessionIDs:       c.SessionIDs,
+		OrderManager:     c.OrderManager,
+		RFQs:             c.rfqs,
+		Securities:       c.securities,
+		DropCopySessions: c.DropCopySessions,
+	}
+	c.fixApp.OnCreate(testSessionID)
+
+	var err error
+	if c.views, err = newViews(false); err != nil {
+		t.Fatal(err)
+	}
+	if err = c.parseRequiredSessions(""); err != nil {
+		t.Fatal(err)
+	}
+
+	router := mux.NewRouter().StrictSlash(true)
+	c.routes(router)
+
+	return c, sender, router
+}
+
+func do(h http.Handler, method, path, body string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, path, strings.NewReader(body))
+	if body != "" {
+		r.Header.Set("Content-Type", "application/json")
+	}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w
+}
+
+// seedOrder saves a working limit order on the test session
+func seedOrder(t *testing.T, c *tradeClient) *oms.Order {
+	t.Helper()
+
+	order := &oms.Order{
+		Session: testSessionID.String(), SessionID: testSessionID, Symbol: "IBM", Side: enum.Side_BUY,
+		Quantity: "100", OrdType: enum.OrdType_LIMIT, Price: "10.5", OrdStatus: enum.OrdStatus_NEW,
+	}
+	if err := order.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	c.Lock()
+	defer c.Unlock()
+	_ = c.Save(order)
+	_ = c.SaveExecution(&oms.Execution{Symbol: "IBM", Side: enum.Side_BUY, Quantity: "10", Price: "10.5", Session: order.Session})
+
+	return order
+}
+
+// seedRFQ saves a quoted request on the test session
+func seedRFQ(t *testing.T, c *tradeClient) {
+	t.Helper()
+
+	req := &rfq.Request{Session: testSessionID.String(), SessionID: testSessionID, Symbol: "IBM", Side: enum.Side_BUY, Quantity: "100"}
+	if err := req.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	c.rfqs.Lock()
+	defer c.rfqs.Unlock()
+	_ = c.rfqs.Save(req)
+	c.rfqs.AddQuote(req, &rfq.Quote{QuoteID: "Q1", BidPx: "10.4", OfferPx: "10.6", ValidUntil: time.Now().Add(time.Hour)})
+}
+
+// seedAlgo submits a TWAP on the test session, which sends its first slice
+func seedAlgo(t *testing.T, c *tradeClient) {
+	t.Helper()
+
+	order := &oms.Order{Session: testSessionID.String(), SessionID: testSessionID, Symbol: "IBM", Side: enum.Side_BUY, Quantity: "100", OrdType: enum.OrdType_MARKET}
+	if err := order.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	params := algo.Params{Strategy: algo.TWAP, Slices: 2, EndTime: time.Now().Add(time.Hour)}
+	if _, err := c.algos.Submit(order, params); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// seedBasket previews a basket of two orders on the test session
+func seedBasket(t *testing.T, h http.Handler) {
+	t.Helper()
+
+	body := `[{"session_id":"FIX.4.2:TW->ISLD","symbol":"IBM","side":"1","quantity":"10","ord_type":"1"},
+		{"session_id":"FIX.4.2:TW->ISLD","symbol":"MSFT","side":"2","quantity":"20","ord_type":"1"}]`
+	if w := do(h, "POST", "/baskets", body); w.Code != http.StatusOK {
+		t.Fatalf("preview basket: %v %v", w.Code, w.Body)
+	}
+}
+
+// seedTemplate creates a market order template on the test session through the api
+func seedTemplate(t *testing.T, h http.Handler) {
+	t.Helper()
+
+	w := do(h, "POST", "/templates", `{"name":"IBM","order":{"session_id":"`+testSessionID.String()+`","symbol":"IBM","side":"1","quantity":"100","ord_type":"1"}}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("new template: %v %v", w.Code, w.Body)
+	}
+}
+
+func TestRoutes(t *testing.T) {
+	session := testSessionID.String()
+
+	tests := []struct {
+		name       string
+		setup      func(t *testing.T, c *tradeClient, h http.Handler)
+		method     string
+		path       string
+		body       string
+		wantStatus int
+		wantSent   []string
+	}{
+		{name: "metrics", method: "GET", path: "/metrics", wantStatus: http.StatusOK},
+		{name: "openapi", method: "GET", path: "/openapi.json", wantStatus: http.StatusOK},
+		{name: "healthz", method: "GET", path: "/healthz", wantStatus: http.StatusOK},
+		{name: "readyz logged out", method: "GET", path: "/readyz", wantStatus: http.StatusServiceUnavailable},
+		{name: "sessions", method: "GET", path: "/sessions", wantStatus: http.StatusOK},
+
+		{name: "new order", method: "POST", path: "/orders", wantStatus: http.StatusOK, wantSent: []string{"D"},
+			body: `{"session_id":"` + session + `","symbol":"IBM","side":"1","quantity":"100","ord_type":"2","price":"10.5"}`},
+		{name: "new multileg order", method: "POST", path: "/orders", wantStatus: http.StatusOK, wantSent: []string{"AB"},
+			body: `{"session_id":"` + session + `","symbol":"IBM","side":"1","quantity":"1","ord_type":"1","legs":[{"symbol":"IBM","side":"1","ratio":"1"},{"symbol":"MSFT","side":"2","ratio":"1"}]}`},
+		{name: "new multileg order without leg side", method: "POST", path: "/orders", wantStatus: http.StatusBadRequest,
+			body: `{"session_id":"` + session + `","symbol":"IBM","side":"1","quantity":"1","ord_type":"1","legs":[{"symbol":"IBM","ratio":"1"},{"symbol":"MSFT","side":"2","ratio":"1"}]}`},
+		{name: "new order invalid json", method: "POST", path: "/orders", body: `{`, wantStatus: http.StatusBadRequest},
+		{name: "new order unknown session", method: "POST", path: "/orders", wantStatus: http.StatusBadRequest,
+			body: `{"session_id":"FIX.4.2:X->Y","symbol":"IBM","side":"1","quantity":"100","ord_type":"1"}`},
+		{name: "new order invalid quantity", method: "POST", path: "/orders", wantStatus: http.StatusBadRequest,
+			body: `{"session_id":"` + session + `","symbol":"IBM","side":"1","quantity":"x","ord_type":"1"}`},
+		{name: "new order build failure", method: "POST", path: "/orders", wantStatus: http.StatusInternalServerError,
+			body: `{"session_id":"` + session + `","symbol":"FAIL","side":"1","quantity":"100","ord_type":"1"}`},
+		{name: "orders", setup: func(t *testing.T, c *tradeClient, _ http.Handler) { seedOrder(t, c) }, method: "GET", path: "/orders", wantStatus: http.StatusOK},
+		{name: "order", setup: func(t *testing.T, c *tradeClient, _ http.Handler) { seedOrder(t, c) }, method: "GET", path: "/orders/1", wantStatus: http.StatusOK},
+		{name: "order not found", method: "GET", path: "/orders/1", wantStatus: http.StatusNotFound},
+		{name: "cancel order", setup: func(t *testing.T, c *tradeClient, _ http.Handler) { seedOrder(t, c) }, method: "DELETE", path: "/orders/1",
+			wantStatus: http.StatusOK, wantSent: []string{"F"}},
+		{name: "cancel order not found", method: "DELETE", path: "/orders/1", wantStatus: http.StatusNotFound},
+		{name: "amend order", setup: func(t *testing.T, c *tradeClient, _ http.Handler) { seedOrder(t, c) }, method: "PATCH", path: "/orders/1",
+			body: `{"quantity":"200"}`, wantStatus: http.StatusOK, wantSent: []string{"G"}},
+		{name: "amend order invalid price", setup: func(t *testing.T, c *tradeClient, _ http.Handler) { seedOrder(t, c) }, method: "PATCH", path: "/orders/1",
+			body: `{"price":"x"}`, wantStatus: http.StatusBadRequest},
+
+		{name: "new template", method: "POST", path: "/templates", wantStatus: http.StatusOK,
+			body: `{"name":"IBM","order":{"session_id":"` + session + `","symbol":"IBM","side":"1","quantity":"100","ord_type":"1"}}`},
+		{name: "templates", setup: func(t *testing.T, _ *tradeClient, h http.Handler) { seedTemplate(t, h) }, method: "GET", path: "/templates", wantStatus: http.StatusOK},
+		{name: "template", setup: func(t *testing.T, _ *tradeClient, h http.Handler) { seedTemplate(t, h) }, method: "GET", path: "/templates/1", wantStatus: http.StatusOK},
+		{name: "template not found", method: "GET", path: "/templates/1", wantStatus: http.StatusNotFound},
+		{name: "update template", setup: func(t *testing.T, _ *tradeClient, h http.Handler) { seedTemplate(t, h) }, method: "PUT", path: "/templates/1",
+			body: `{"name":"IBM 200","order":{"symbol":"IBM","quantity":"200"}}`, wantStatus: http.StatusOK},
+		{name: "delete template", setup: func(t *testing.T, _ *tradeClient, h http.Handler) { seedTemplate(t, h) }, method: "DELETE", path: "/templates/1", wantStatus: http.StatusNoContent},
+		{name: "submit template", setup: func(t *testing.T, _ *tradeClient, h http.Handler) { seedTemplate(t, h) }, method: "POST", path: "/templates/1/submit",
+			body: `{"quantity":"50"}`, wantStatus: http.StatusOK, wantSent: []string{"D"}},
+
+		{name: "executions", setup: func(t *testing.T, c *tradeClient, _ http.Handler) { seedOrder(t, c) }, method: "GET", path: "/executions", wantStatus: http.StatusOK},
+		{name: "execution", setup: func(t *testing.T, c *tradeClient, _ http.Handler) { seedOrder(t, c) }, method: "GET", path: "/executions/1", wantStatus: http.StatusOK},
+		{name: "execution not found", method: "GET", path: "/executions/1", wantStatus: http.StatusNotFound},
+
+		{name: "new algo", method: "POST", path: "/algos", wantStatus: http.StatusOK, wantSent: []string{"D"},
+			body: `{"session_id":"` + session + `","symbol":"IBM","side":"1","quantity":"100","ord_type":"1","algo":{"strategy":"TWAP","slices":2,"end_time":"2100-01-01T00:00:00Z"}}`},
+		{name: "new algo invalid params", method: "POST", path: "/algos", wantStatus: http.StatusBadRequest,
+			body: `{"session_id":"` + session + `","symbol":"IBM","side":"1","quantity":"100","ord_type":"1","algo":{"strategy":"TWAP"}}`},
+		{name: "algos", setup: func(t *testing.T, c *tradeClient, _ http.Handler) { seedAlgo(t, c) }, method: "GET", path: "/algos", wantStatus: http.StatusOK, wantSent: []string{"D"}},
+		{name: "algo", setup: func(t *testing.T, c *tradeClient, _ http.Handler) { seedAlgo(t, c) }, method: "GET", path: "/algos/1", wantStatus: http.StatusOK, wantSent: []string{"D"}},
+		{name: "algo not found", method: "GET", path: "/algos/1", wantStatus: http.StatusNotFound},
+		{name: "pause algo", setup: func(t *testing.T, c *tradeClient, _ http.Handler) { seedAlgo(t, c) }, method: "POST", path: "/algos/1/pause", wantStatus: http.StatusOK, wantSent: []string{"D"}},
+		{name: "resume running algo", setup: func(t *testing.T, c *tradeClient, _ http.Handler) { seedAlgo(t, c) }, method: "POST", path: "/algos/1/resume", wantStatus: http.StatusConflict, wantSent: []string{"D"}},
+		{name: "cancel algo", setup: func(t *testing.T, c *tradeClient, _ http.Handler) { seedAlgo(t, c) }, method: "DELETE", path: "/algos/1", wantStatus: http.StatusOK, wantSent: []string{"D", "F"}},
+
+		{name: "new basket", method: "POST", path: "/baskets", wantStatus: http.StatusOK,
+			body: `[{"session_id":"` + session + `","symbol":"IBM","side":"1","quantity":"10","ord_type":"1"}]`},
+		{name: "baskets", setup: func(t *testing.T, _ *tradeClient, h http.Handler) { seedBasket(t, h) }, method: "GET", path: "/baskets", wantStatus: http.StatusOK},
+		{name: "basket", setup: func(t *testing.T, _ *tradeClient, h http.Handler) { seedBasket(t, h) }, method: "GET", path: "/baskets/1", wantStatus: http.StatusOK},
+		{name: "basket not found", method: "GET", path: "/baskets/1", wantStatus: http.StatusNotFound},
+		{name: "confirm basket", setup: func(t *testing.T, _ *tradeClient, h http.Handler) { seedBasket(t, h) }, method: "POST", path: "/baskets/1/confirm",
+			wantStatus: http.StatusOK, wantSent: []string{"D", "D"}},
+		{name: "discard basket", setup: func(t *testing.T, _ *tradeClient, h http.Handler) { seedBasket(t, h) }, method: "DELETE", path: "/baskets/1", wantStatus: http.StatusOK},
+
+		{name: "new rfq", method: "POST", path: "/rfqs", wantStatus: http.StatusOK, wantSent: []string{"R"},
+			body: `{"session_id":"` + session + `","symbol":"IBM","side":"1","quantity":"100"}`},
+		{name: "rfqs", setup: func(t *testing.T, c *tradeClient, _ http.Handler) { seedRFQ(t, c) }, method: "GET", path: "/rfqs", wantStatus: http.StatusOK},
+		{name: "rfq", setup: func(t *testing.T, c *tradeClient, _ http.Handler) { seedRFQ(t, c) }, method: "GET", path: "/rfqs/1", wantStatus: http.StatusOK},
+		{name: "rfq not found", method: "GET", path: "/rfqs/1", wantStatus: http.StatusNotFound},
+		{name: "hit quote", setup: func(t *testing.T, c *tradeClient, _ http.Handler) { seedRFQ(t, c) }, method: "POST", path: "/rfqs/1/quotes/Q1/hit",
+			body: `{}`, wantStatus: http.StatusOK, wantSent: []string{"D"}},
+		{name: "hit unknown quote", setup: func(t *testing.T, c *tradeClient, _ http.Handler) { seedRFQ(t, c) }, method: "POST", path: "/rfqs/1/quotes/Q2/hit",
+			body: `{}`, wantStatus: http.StatusNotFound},
+
+		{name: "securities", method: "GET", path: "/securities", wantStatus: http.StatusOK},
+		{name: "security", method: "GET", path: "/securities/IBM", wantStatus: http.StatusOK,
+			setup: func(t *testing.T, c *tradeClient, h http.Handler) {
+				c.securities.Put(secmaster.Security{Symbol: "IBM"})
+			}},
+		{name: "security not found", method: "GET", path: "/securities/IBM", wantStatus: http.StatusNotFound},
+
+		{name: "security definition request", method: "POST", path: "/securitydefinitionrequest", wantStatus: http.StatusOK, wantSent: []string{"c"},
+			body: `{"session_id":"` + session + `","security_request_type":"0","symbol":"IBM"}`},
+		{name: "security definition request unknown session", method: "POST", path: "/securitydefinitionrequest", wantStatus: http.StatusBadRequest,
+			body: `{"session_id":"FIX.4.2:X->Y","symbol":"IBM"}`},
+
+		{name: "trader view", method: "GET", path: "/", wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, sender, h := newTestClient(t)
+			if tt.setup != nil {
+				tt.setup(t, c, h)
+			}
+
+			w := do(h, tt.method, tt.path, tt.body)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("got status %v, want %v: %v", w.Code, tt.wantStatus, w.Body)
+			}
+
+			if got := sender.msgTypes(); strings.Join(got, ",") != strings.Join(tt.wantSent, ",") {
+				t.Errorf("sent %v, want %v", got, tt.wantSent)
+			}
+		})
+	}
+}
+
+func TestNewOrderSendsSavedOrder(t *testing.T) {
+	c, sender, h := newTestClient(t)
+
+	w := do(h, "POST", "/orders", `{"session_id":"`+testSessionID.String()+`","symbol":"IBM","side":"1","quantity":"100","ord_type":"1"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("got status %v: %v", w.Code, w.Body)
+	}
+
+	var order oms.Order
+	if err := json.NewDecoder(w.Body).Decode(&order); err != nil {
+		t.Fatal(err)
+	}
+
+	c.RLock()
+	saved, err := c.Get(order.ID)
+	c.RUnlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clOrdID, _ := sender.msgs[0].Body.GetString(tag.ClOrdID)
+	if clOrdID != saved.ClOrdID || order.ClOrdID != saved.ClOrdID {
+		t.Errorf("sent clordid %v, responded %v, saved %v", clOrdID, order.ClOrdID, saved.ClOrdID)
+	}
+}
+
+func TestCancelOrderLinksClOrdID(t *testing.T) {
+	c, sender, h := newTestClient(t)
+	order := seedOrder(t, c)
+
+	if w := do(h, "DELETE", "/orders/1", ""); w.Code != http.StatusOK {
+		t.Fatalf("got status %v: %v", w.Code, w.Body)
+	}
+
+	clOrdID, _ := sender.msgs[0].Body.GetString(tag.ClOrdID)
+	origClOrdID, _ := sender.msgs[0].Body.GetString(tag.OrigClOrdID)
+	if origClOrdID != order.ClOrdID || clOrdID == order.ClOrdID {
+		t.Errorf("got clordid %v orig %v for order %v", clOrdID, origClOrdID, order.ClOrdID)
+	}
+
+	c.RLock()
+	linked, err := c.GetByClOrdID(clOrdID)
+	c.RUnlock()
+	if err != nil || linked != order {
+		t.Errorf("cancel clordid %v is not linked to the order: %v", clOrdID, err)
+	}
+}
+
+func TestAmendOrderKeepsOrderUntilReplaced(t *testing.T) {
+	c, sender, h := newTestClient(t)
+	order := seedOrder(t, c)
+
+	if w := do(h, "PATCH", "/orders/1", `{"quantity":"200","price":"11"}`); w.Code != http.StatusOK {
+		t.Fatalf("got status %v: %v", w.Code, w.Body)
+	}
+
+	qty, _ := sender.msgs[0].Body.GetString(tag.OrderQty)
+	price, _ := sender.msgs[0].Body.GetString(tag.Price)
+	if qty != "200" || price != "11" {
+		t.Errorf("sent quantity %v price %v, want 200 11", qty, price)
+	}
+	if order.Quantity != "100" || order.Price != "10.5" {
+		t.Errorf("order amended to %v %v before the counterparty confirmed", order.Quantity, order.Price)
+	}
+}
+
+func TestAmendBelowFilledQuantity(t *testing.T) {
+	c, sender, h := newTestClient(t)
+	order := seedOrder(t, c)
+	c.Lock()
+	order.Closed, order.Open, order.OrdStatus = "60", "40", enum.OrdStatus_PARTIALLY_FILLED
+	c.Unlock()
+
+	for _, qty := range []string{"50", "60"} {
+		if w := do(h, "PATCH", "/orders/1", `{"quantity":"`+qty+`"}`); w.Code != http.StatusBadRequest {
+			t.Errorf("amend to %v: got status %v, want %v", qty, w.Code, http.StatusBadRequest)
+		}
+	}
+	if w := do(h, "PATCH", "/orders/1", `{"quantity":"70"}`); w.Code != http.StatusOK {
+		t.Errorf("amend to 70: got status %v: %v", w.Code, w.Body)
+	}
+
+	if got := sender.msgTypes(); strings.Join(got, ",") != "G" {
+		t.Errorf("sent %v, want only the valid amendment", got)
+	}
+}
+
+func TestNewOrderServerFields(t *testing.T) {
+	c, _, h := newTestClient(t)
+
+	w := do(h, "POST", "/orders", `{"session_id":"`+testSessionID.String()+`","symbol":"IBM","side":"1","quantity":"100","ord_type":"1",
+		"id":7,"parent_id":3,"clord_id":"X","external":true,"ord_status":"2","closed":"100","open":"0","avg_px":"1","user":"mallory"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("got status %v %v", w.Code, w.Body)
+	}
+
+	c.RLock()
+	defer c.RUnlock()
+	order, err := c.Get(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if order.External || order.ParentID != 0 || order.ClOrdID == "X" || order.OrdStatus != "" || order.Closed != "" || order.AvgPx != "" || order.User == "mallory" {
+		t.Errorf("got %+v, want the fields the server owns ignored", order)
+	}
+}
+
+func TestSendFailure(t *testing.T) {
+	c, sender, h := newTestClient(t)
+	sender.err = errors.New("session not logged on")
+
+	w := do(h, "POST", "/orders", `{"session_id":"`+testSessionID.String()+`","symbol":"IBM","side":"1","quantity":"100","ord_type":"1"}`)
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("got status %v, want %v", w.Code, http.StatusInternalServerError)
+	}
+
+	c.RLock()
+	defer c.RUnlock()
+	if order, err := c.Get(1); err != nil || order.OrdStatus != enum.OrdStatus_REJECTED || order.Open != "0" {
+		t.Errorf("got %+v %v, want the unsent order rejected", order, err)
+	}
+}
+
+func TestPaperSessionValidation(t *testing.T) {
+	c, sender, h := newTestClient(t)
+	c.PaperSessions[testSessionID] = true
+	session := testSessionID.String()
+
+	w := do(h, "POST", "/orders", `{"session_id":"`+session+`","symbol":"IBM","side":"1","quantity":"1","ord_type":"1","legs":[{"symbol":"IBM","side":"1","ratio":"1"},{"symbol":"MSFT","side":"2","ratio":"1"}]}`)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("multileg order: got status %v, want %v", w.Code, http.StatusBadRequest)
+	}
+
+	body := `[{"session_id":"` + session + `","symbol":"IBM","side":"1","quantity":"10","ord_type":"1"}]`
+	if w = do(h, "POST", "/baskets?mode=list", body); w.Code != http.StatusOK {
+		t.Fatalf("preview basket: %v %v", w.Code, w.Body)
+	}
+	if w = do(h, "POST", "/baskets/1/confirm", ""); w.Code != http.StatusBadRequest {
+		t.Errorf("order list: got status %v, want %v", w.Code, http.StatusBadRequest)
+	}
+
+	c.RLock()
+	defer c.RUnlock()
+	if n := len(c.GetAll()); n != 0 || len(sender.msgTypes()) != 0 {
+		t.Errorf("saved %v orders and sent %v, want the orders rejected before saving", n, sender.msgTypes())
+	}
+}
+
+func TestListBasketValidation(t *testing.T) {
+	c, sender, h := newTestClient(t)
+	fix41 := quickfix.SessionID{BeginString: quickfix.BeginStringFIX41, SenderCompID: "TW", TargetCompID: "ISLD"}
+	c.fixApp.OnCreate(fix41)
+
+	order := `{"session_id":"%v","symbol":"IBM","side":"1","quantity":"10","ord_type":"1"%v}`
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"FIX.4.1", fmt.Sprintf(order, fix41, "")},
+		{"legs", fmt.Sprintf(order, testSessionID, `,"legs":[{"symbol":"IBM","side":"1","ratio":"1"},{"symbol":"MSFT","side":"2","ratio":"1"}]`)},
+		{"quote", fmt.Sprintf(order, testSessionID, `,"quote_id":"Q1"`)},
+	}
+
+	for i, tt := range tests {
+		w := do(h, "POST", "/baskets?mode=list", "["+tt.body+"]")
+		if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "order list") {
+			t.Errorf("%v: got %v %v, want the row rejected in the preview", tt.name, w.Code, w.Body)
+		}
+		if w = do(h, "POST", fmt.Sprintf("/baskets/%v/confirm", i+1), ""); w.Code != http.StatusBadRequest {
+			t.Errorf("%v: got status %v, want %v", tt.name, w.Code, http.StatusBadRequest)
+		}
+	}
+
+	c.RLock()
+	defer c.RUnlock()
+	if n := len(c.GetAll()); n != 0 || len(sender.msgTypes()) != 0 {
+		t.Errorf("saved %v orders and sent %v, want the orders rejected before saving", n, sender.msgTypes())
+	}
+}
+
+func TestRFQSendFailure(t *testing.T) {
+	c, sender, h := newTestClient(t)
+	sender.err = errors.New("session not logged on")
+
+	w := do(h, "POST", "/rfqs", `{"session_id":"`+testSessionID.String()+`","symbol":"IBM","side":"1","quantity":"100"}`)
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("got status %v, want %v", w.Code, http.StatusInternalServerError)
+	}
+
+	c.rfqs.RLock()
+	defer c.rfqs.RUnlock()
+	if req, err := c.rfqs.Get(1); err != nil || req.Status != rfq.Cancelled {
+		t.Errorf("got %+v %v, want the unsent request cancelled", req, err)
+	}
+}
+
+func TestThrottledOrder(t *testing.T) {
+	c, capture, _ := newTestClient(t)
+	c.throttle = sender.NewThrottle(sender.Limit{Rate: 1, Burst: 1}, nil, throttledMsgTypes...)
+	c.sender = sender.Chain(capture, c.throttle.Middleware)
+
+	router := mux.NewRouter().StrictSlash(true)
+	c.routes(router)
+
+	body := `{"session_id":"` + testSessionID.String() + `","symbol":"IBM","side":"1","quantity":"100","ord_type":"1"}`
+	for i, want := range []int{http.StatusOK, http.StatusTooManyRequests} {
+		if w := do(router, "POST", "/orders", body); w.Code != want {
+			t.Errorf("order %v: got status %v, want %v", i, w.Code, want)
+		}
+	}
+
+	var health healthStatus
+	if err := json.NewDecoder(do(router, "GET", "/healthz", "").Body).Decode(&health); err != nil {
+		t.Fatal(err)
+	}
+	if len(health.Sessions) != 1 || health.Sessions[0].Throttle == nil || health.Sessions[0].Throttle.Rejected != 1 {
+		t.Errorf("got sessions %+v, want the throttle of the session with 1 rejected", health.Sessions)
+	}
+}
+
+func TestTemplates(t *testing.T) {
+	c, sender, h := newTestClient(t)
+
+	w := do(h, "POST", "/templates", `{"name":"IBM bid","order":{"session_id":"`+testSessionID.String()+`","symbol":"IBM","side":"1","quantity":"100","ord_type":"2","price":"10","ord_status":"2"}}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("got status %v: %v", w.Code, w.Body)
+	}
+	var tmpl oms.Template
+	if err := json.NewDecoder(w.Body).Decode(&tmpl); err != nil {
+		t.Fatal(err)
+	}
+	if tmpl.ID != 1 || tmpl.Order.OrdStatus != "" {
+		t.Errorf("got template %+v, want id 1 without order state", tmpl)
+	}
+
+	if w = do(h, "POST", "/templates", `{"order":{"symbol":"IBM"}}`); w.Code != http.StatusBadRequest {
+		t.Errorf("template without name: got status %v, want %v", w.Code, http.StatusBadRequest)
+	}
+
+	w = do(h, "POST", "/templates/1/submit", `{"quantity":"250"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("got status %v: %v", w.Code, w.Body)
+	}
+	var order oms.Order
+	if err := json.NewDecoder(w.Body).Decode(&order); err != nil {
+		t.Fatal(err)
+	}
+	if order.Quantity != "250" || order.Price != "10" || order.Symbol != "IBM" || len(sender.msgs) != 1 {
+		t.Errorf("got order %v of %v at %v and %v messages, want 250 IBM at 10 sent", order.Quantity, order.Symbol, order.Price, len(sender.msgs))
+	}
+
+	c.RLock()
+	tmplQuantity := c.GetTemplates()[0].Order.Quantity
+	c.RUnlock()
+	if tmplQuantity != "100" {
+		t.Errorf("got template quantity %v after submit, want 100", tmplQuantity)
+	}
+
+	if w = do(h, "PUT", "/templates/1", `{"name":"IBM","order":{"symbol":"IBM","side":"1","quantity":"100","ord_type":"2","price":"10"}}`); w.Code != http.StatusOK {
+		t.Fatalf("got status %v: %v", w.Code, w.Body)
+	}
+	if w = do(h, "POST", "/templates/1/submit", ""); w.Code != http.StatusBadRequest {
+		t.Errorf("submit without session: got status %v, want %v", w.Code, http.StatusBadRequest)
+	}
+
+	if w = do(h, "DELETE", "/templates/1", ""); w.Code != http.StatusNoContent {
+		t.Errorf("got status %v, want %v", w.Code, http.StatusNoContent)
+	}
+	if w = do(h, "GET", "/templates/1", ""); w.Code != http.StatusNotFound {
+		t.Errorf("got status %v, want %v", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestSecurityMasterValidation(t *testing.T) {
+	c, sender, _ := newTestClient(t)
+	c.securities.Put(secmaster.Security{Symbol: "IBM", TickSize: decimal.RequireFromString("0.05"), LotSize: decimal.RequireFromString("100")})
+	c.validateSecurities = true
+
+	router := mux.NewRouter().StrictSlash(true)
+	c.routes(router)
+
+	session := testSessionID.String()
+	tests := []struct {
+		name, body string
+		wantStatus int
+	}{
+		{"valid", `{"session_id":"` + session + `","symbol":"IBM","side":"1","quantity":"200","ord_type":"2","price":"10.55"}`, http.StatusOK},
+		{"unknown symbol", `{"session_id":"` + session + `","symbol":"MSFT","side":"1","quantity":"100","ord_type":"1"}`, http.StatusBadRequest},
+		{"off tick", `{"session_id":"` + session + `","symbol":"IBM","side":"1","quantity":"100","ord_type":"2","price":"10.51"}`, http.StatusBadRequest},
+		{"odd lot", `{"session_id":"` + session + `","symbol":"IBM","side":"1","quantity":"150","ord_type":"1"}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		if w := do(router, "POST", "/orders", tt.body); w.Code != tt.wantStatus {
+			t.Errorf("%v: got status %v, want %v: %v", tt.name, w.Code, tt.wantStatus, w.Body)
+		}
+	}
+
+	if w := do(router, "PATCH", "/orders/1", `{"price":"10.57"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("amend off tick: got status %v, want %v", w.Code, http.StatusBadRequest)
+	}
+
+	if got := sender.msgTypes(); strings.Join(got, ",") != "D" {
+		t.Errorf("sent %v, want only the valid order", got)
+	}
+}
+
+func TestExecutionsScopedByAccount(t *testing.T) {
+	c, _, h := newTestClient(t)
+
+	hash, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	usersFile := filepath.Join(t.TempDir(), "users.json")
+	users := `{"roles":{"desk":{"sessions":["*"],"accounts":["A"]}},"users":[{"username":"alice","password_hash":"` + string(hash) + `","role":"desk"}]}`
+	if err = os.WriteFile(usersFile, []byte(users), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := auth.LoadUsers(usersFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	authenticator := auth.NewAuthenticator(loaded)
+	session, ok := authenticator.Login("alice", "secret")
+	if !ok {
+		t.Fatal("login failed")
+	}
+
+	c.Lock()
+	_ = c.SaveExecution(&oms.Execution{Symbol: "IBM", Quantity: "10", Price: "10", Session: testSessionID.String(), Account: "A"})
+	_ = c.SaveExecution(&oms.Execution{Symbol: "IBM", Quantity: "20", Price: "10", Session: testSessionID.String(), Account: "B"})
+	c.Unlock()
+
+	get := func(path string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest("GET", path, nil)
+		r.Header.Set("Authorization", "Bearer "+session.Token)
+		w := httptest.NewRecorder()
+		authenticator.Middleware(h).ServeHTTP(w, r)
+		return w
+	}
+
+	var executions []oms.Execution
+	if err = json.NewDecoder(get("/executions").Body).Decode(&executions); err != nil {
+		t.Fatal(err)
+	}
+	if len(executions) != 1 || executions[0].Account != "A" {
+		t.Errorf("got executions %+v, want only those of account A", executions)
+	}
+
+	if w := get("/executions/2"); w.Code != http.StatusNotFound {
+		t.Errorf("got status %v for an execution of account B, want %v", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestCancelOwnership(t *testing.T) {
+	c, sender, h := newTestClient(t)
+
+	hash, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	usersFile := filepath.Join(t.TempDir(), "users.json")
+	users := `{"users":[{"username":"alice","password_hash":"` + string(hash) + `"},{"username":"bob","password_hash":"` + string(hash) + `"},` +
+		`{"username":"sam","password_hash":"` + string(hash) + `","role":"supervisor"}]}`
+	if err = os.WriteFile(usersFile, []byte(users), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := auth.LoadUsers(usersFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	authenticator := auth.NewAuthenticator(loaded)
+
+	order := seedOrder(t, c)
+	c.Lock()
+	order.User = "alice"
+	c.Unlock()
+
+	cancel := func(username string) int {
+		session, ok := authenticator.Login(username, "secret")
+		if !ok {
+			t.Fatalf("login of %v failed", username)
+		}
+
+		r := httptest.NewRequest("DELETE", "/orders/1", nil)
+		r.Header.Set("Authorization", "Bearer "+session.Token)
+		w := httptest.NewRecorder()
+		authenticator.Middleware(h).ServeHTTP(w, r)
+		return w.Code
+	}
+
+	if code := cancel("bob"); code != http.StatusForbidden || len(sender.msgTypes()) != 0 {
+		t.Errorf("cancel by another trader: got %v and sent %v, want %v", code, sender.msgTypes(), http.StatusForbidden)
+	}
+	if code := cancel("sam"); code != http.StatusOK || strings.Join(sender.msgTypes(), " ") != "F" {
+		t.Errorf("cancel by a supervisor: got %v and sent %v, want %v", code, sender.msgTypes(), http.StatusOK)
+	}
+}
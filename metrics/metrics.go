@@ -0,0 +1,193 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// Rejection sources
+const (
+	// RejectedValidation is an order refused before it was sent
+	RejectedValidation = "validation"
+	// RejectedCounterparty is an order rejected by the counterparty
+	RejectedCounterparty = "counterparty"
+)
+
+// Metrics are the order flow, FIX session and HTTP metrics of the trader ui. All methods are
+// no-ops on a nil *Metrics.
+type Metrics struct {
+	*Registry
+
+	ordersSubmitted  *Counter
+	ordersRejected   *Counter
+	executionReports *Counter
+	roundTrip        *Histogram
+	loggedOn         *Gauge
+	messagesIn       *Counter
+	messagesOut      *Counter
+	sendDuration     *Histogram
+	httpDuration     *Histogram
+
+	mu sync.Mutex
+	// sent holds the send time of orders awaiting their first execution report, by ClOrdID
+	sent      map[string]time.Time
+	lastSweep time.Time
+}
+
+// sentTTL is how long an order waits for its first execution report before its round trip is
+// dropped, the largest round-trip bucket
+var sentTTL = time.Duration(DefBuckets[len(DefBuckets)-1] * float64(time.Second))
+
+// New returns the metrics registered in a new registry
+func New() *Metrics {
+	r := new(Registry)
+	return &Metrics{
+		Registry:         r,
+		ordersSubmitted:  r.NewCounter("traderui_orders_submitted_total", "Orders sent to a counterparty.", "session"),
+		ordersRejected:   r.NewCounter("traderui_orders_rejected_total", "Orders rejected before sending or by the counterparty.", "session", "source"),
+		executionReports: r.NewCounter("traderui_execution_reports_total", "Execution reports received.", "session", "exec_type"),
+		roundTrip:        r.NewHistogram("traderui_order_round_trip_seconds", "Time from sending an order to its first execution report.", DefBuckets, "session"),
+		loggedOn:         r.NewGauge("traderui_session_logged_on", "1 if the FIX session is logged on.", "session"),
+		messagesIn:       r.NewCounter("traderui_fix_messages_received_total", "FIX messages received.", "session", "msg_type"),
+		messagesOut:      r.NewCounter("traderui_fix_messages_sent_total", "FIX messages sent.", "session", "msg_type"),
+		sendDuration:     r.NewHistogram("traderui_fix_send_duration_seconds", "Time taken to hand a message to its session.", DefBuckets, "session", "msg_type"),
+		httpDuration:     r.NewHistogram("traderui_http_request_duration_seconds", "Latency of http handlers.", DefBuckets, "method", "route", "code"),
+		sent:             make(map[string]time.Time),
+	}
+}
+
+// OrderSubmitted counts an order sent on session
+func (m *Metrics) OrderSubmitted(session string) {
+	if m == nil {
+		return
+	}
+
+	m.ordersSubmitted.Inc(session)
+}
+
+// OrderRejected counts an order on session rejected by source
+func (m *Metrics) OrderRejected(session, source string) {
+	if m == nil {
+		return
+	}
+
+	m.ordersRejected.Inc(session, source)
+}
+
+// OrderSent starts the round-trip timer of clOrdID. Timers of orders that got no report within
+// sentTTL are dropped, so orders never reported on are not kept forever.
+func (m *Metrics) OrderSent(clOrdID string) {
+	if m == nil {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(m.lastSweep) > sentTTL {
+		for id, sent := range m.sent {
+			if now.Sub(sent) > sentTTL {
+				delete(m.sent, id)
+			}
+		}
+		m.lastSweep = now
+	}
+
+	m.sent[clOrdID] = now
+}
+
+// ExecutionReport counts a report on session and stops the round-trip timer of clOrdID
+func (m *Metrics) ExecutionReport(session, clOrdID, execType string) {
+	if m == nil {
+		return
+	}
+
+	m.executionReports.Inc(session, execType)
+
+	m.mu.Lock()
+	sent, ok := m.sent[clOrdID]
+	delete(m.sent, clOrdID)
+	m.mu.Unlock()
+
+	if ok {
+		m.roundTrip.Observe(time.Since(sent).Seconds(), session)
+	}
+}
+
+// LoggedOn sets the logon state of session
+func (m *Metrics) LoggedOn(session string, loggedOn bool) {
+	if m == nil {
+		return
+	}
+
+	v := 0.0
+	if loggedOn {
+		v = 1
+	}
+	m.loggedOn.Set(v, session)
+}
+
+// MessageReceived counts an incoming message
+func (m *Metrics) MessageReceived(session, msgType string) {
+	if m == nil {
+		return
+	}
+
+	m.messagesIn.Inc(session, msgType)
+}
+
+// MessageSent counts an outgoing message
+func (m *Metrics) MessageSent(session, msgType string) {
+	if m == nil {
+		return
+	}
+
+	m.messagesOut.Inc(session, msgType)
+}
+
+// SendDuration records the time taken to send a message
+func (m *Metrics) SendDuration(session, msgType string, d time.Duration) {
+	if m == nil {
+		return
+	}
+
+	m.sendDuration.Observe(d.Seconds(), session, msgType)
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusRecorder) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Middleware times handlers by the route template of the request
+func (m *Metrics) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if m == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		route := r.URL.Path
+		if current := mux.CurrentRoute(r); current != nil {
+			if tmpl, err := current.GetPathTemplate(); err == nil {
+				route = tmpl
+			}
+		}
+
+		m.httpDuration.Observe(time.Since(start).Seconds(), r.Method, route, strconv.Itoa(rec.status))
+	})
+}
@@ -0,0 +1,25 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOrderSentExpires(t *testing.T) {
+	m := New()
+	m.sent["OLD"] = time.Now().Add(-2 * sentTTL)
+	m.sent["RECENT"] = time.Now()
+
+	m.OrderSent("NEW")
+	if _, ok := m.sent["OLD"]; ok {
+		t.Error("expected the order without a report to expire")
+	}
+	if len(m.sent) != 2 {
+		t.Errorf("got %v timers, want the recent and new orders", len(m.sent))
+	}
+
+	m.ExecutionReport("FIX.4.2:TW->ISLD", "NEW", "0")
+	if _, ok := m.sent["NEW"]; ok {
+		t.Error("expected the report to stop the timer")
+	}
+}
@@ -0,0 +1,242 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefBuckets are histogram buckets in seconds suited to request and round-trip latencies
+var DefBuckets = []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// labelEscaper escapes label values as the text format requires
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// labelSep joins label values into a series key, it cannot appear in valid utf-8
+const labelSep = "\xff"
+
+type collector interface {
+	write(w io.Writer)
+}
+
+// vec holds the series of a metric by label values
+type vec struct {
+	sync.Mutex
+	name   string
+	help   string
+	typ    string
+	labels []string
+}
+
+func (v *vec) key(values []string) string {
+	if len(values) != len(v.labels) {
+		panic(fmt.Sprintf("metric %v expects labels %v, got %v", v.name, v.labels, values))
+	}
+
+	return strings.Join(values, labelSep)
+}
+
+func (v *vec) writeHeader(w io.Writer) {
+	fmt.Fprintf(w, "# HELP %v %v\n", v.name, v.help)
+	fmt.Fprintf(w, "# TYPE %v %v\n", v.name, v.typ)
+}
+
+// labelPairs formats the label values of key, with any extra pairs appended
+func (v *vec) labelPairs(key string, extra ...string) string {
+	var pairs []string
+	if len(v.labels) > 0 {
+		for i, value := range strings.Split(key, labelSep) {
+			pairs = append(pairs, fmt.Sprintf(`%v="%v"`, v.labels[i], labelEscaper.Replace(value)))
+		}
+	}
+
+	pairs = append(pairs, extra...)
+	if len(pairs) == 0 {
+		return ""
+	}
+
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// Counter is a monotonically increasing value per label values
+type Counter struct {
+	vec
+	values map[string]float64
+}
+
+// Inc adds one to the series of values
+func (c *Counter) Inc(values ...string) {
+	c.Add(1, values...)
+}
+
+// Add adds delta to the series of values
+func (c *Counter) Add(delta float64, values ...string) {
+	c.Lock()
+	defer c.Unlock()
+
+	c.values[c.key(values)] += delta
+}
+
+func (c *Counter) write(w io.Writer) {
+	c.Lock()
+	defer c.Unlock()
+
+	c.writeHeader(w)
+	for _, k := range sortedKeys(c.values) {
+		fmt.Fprintf(w, "%v%v %v\n", c.name, c.labelPairs(k), formatFloat(c.values[k]))
+	}
+}
+
+// Gauge is a value that can go up and down per label values
+type Gauge struct {
+	vec
+	values map[string]float64
+}
+
+// Set sets the series of values to v
+func (g *Gauge) Set(v float64, values ...string) {
+	g.Lock()
+	defer g.Unlock()
+
+	g.values[g.key(values)] = v
+}
+
+func (g *Gauge) write(w io.Writer) {
+	g.Lock()
+	defer g.Unlock()
+
+	g.writeHeader(w)
+	for _, k := range sortedKeys(g.values) {
+		fmt.Fprintf(w, "%v%v %v\n", g.name, g.labelPairs(k), formatFloat(g.values[k]))
+	}
+}
+
+type histogramSeries struct {
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// Histogram counts observations in cumulative buckets per label values
+type Histogram struct {
+	vec
+	buckets []float64
+	series  map[string]*histogramSeries
+}
+
+// Observe records v in the series of values
+func (h *Histogram) Observe(v float64, values ...string) {
+	h.Lock()
+	defer h.Unlock()
+
+	k := h.key(values)
+	s, ok := h.series[k]
+	if !ok {
+		s = &histogramSeries{counts: make([]uint64, len(h.buckets))}
+		h.series[k] = s
+	}
+
+	for i, upper := range h.buckets {
+		if v <= upper {
+			s.counts[i]++
+		}
+	}
+	s.count++
+	s.sum += v
+}
+
+func (h *Histogram) write(w io.Writer) {
+	h.Lock()
+	defer h.Unlock()
+
+	h.writeHeader(w)
+	for _, k := range sortedKeys(h.series) {
+		s := h.series[k]
+		for i, upper := range h.buckets {
+			fmt.Fprintf(w, "%v_bucket%v %v\n", h.name, h.labelPairs(k, fmt.Sprintf(`le="%v"`, formatFloat(upper))), s.counts[i])
+		}
+		fmt.Fprintf(w, "%v_bucket%v %v\n", h.name, h.labelPairs(k, `le="+Inf"`), s.count)
+		fmt.Fprintf(w, "%v_sum%v %v\n", h.name, h.labelPairs(k), formatFloat(s.sum))
+		fmt.Fprintf(w, "%v_count%v %v\n", h.name, h.labelPairs(k), s.count)
+	}
+}
+
+// Registry holds metrics and serves them in the prometheus text exposition format
+type Registry struct {
+	sync.Mutex
+	collectors []collector
+}
+
+func (r *Registry) register(c collector) {
+	r.Lock()
+	defer r.Unlock()
+
+	r.collectors = append(r.collectors, c)
+}
+
+// NewCounter registers a counter with the label names
+func (r *Registry) NewCounter(name, help string, labels ...string) *Counter {
+	c := &Counter{vec: vec{name: name, help: help, typ: "counter", labels: labels}, values: make(map[string]float64)}
+	r.register(c)
+
+	return c
+}
+
+// NewGauge registers a gauge with the label names
+func (r *Registry) NewGauge(name, help string, labels ...string) *Gauge {
+	g := &Gauge{vec: vec{name: name, help: help, typ: "gauge", labels: labels}, values: make(map[string]float64)}
+	r.register(g)
+
+	return g
+}
+
+// NewHistogram registers a histogram with the bucket upper bounds and label names
+func (r *Registry) NewHistogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	h := &Histogram{
+		vec:     vec{name: name, help: help, typ: "histogram", labels: labels},
+		buckets: buckets,
+		series:  make(map[string]*histogramSeries),
+	}
+	r.register(h)
+
+	return h
+}
+
+// WriteText writes all metrics to w in the text format
+func (r *Registry) WriteText(w io.Writer) {
+	r.Lock()
+	collectors := r.collectors
+	r.Unlock()
+
+	for _, c := range collectors {
+		c.write(w)
+	}
+}
+
+// ServeHTTP serves the metrics to a prometheus scrape
+func (r *Registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+
+	bw := bufio.NewWriter(w)
+	r.WriteText(bw)
+	bw.Flush()
+}
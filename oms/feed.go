@@ -0,0 +1,81 @@
+package oms
+
+import "sync"
+
+// subscriptionBuffer is how many updates a subscriber may fall behind before it is dropped
+const subscriptionBuffer = 256
+
+// Subscription receives snapshots of updates on C until closed. C is closed when the
+// subscription is closed or the subscriber falls too far behind.
+type Subscription[T any] struct {
+	C <-chan T
+
+	c    chan T
+	feed *feed[T]
+}
+
+// Close stops the subscription
+func (s *Subscription[T]) Close() {
+	s.feed.remove(s)
+}
+
+// feed fans out snapshots to its subscribers. It has its own lock so it may be published to
+// while the order manager is locked.
+type feed[T any] struct {
+	mu   sync.Mutex
+	subs map[*Subscription[T]]bool
+}
+
+func (f *feed[T]) subscribe() *Subscription[T] {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	c := make(chan T, subscriptionBuffer)
+	s := &Subscription[T]{C: c, c: c, feed: f}
+	if f.subs == nil {
+		f.subs = make(map[*Subscription[T]]bool)
+	}
+	f.subs[s] = true
+
+	return s
+}
+
+func (f *feed[T]) remove(s *Subscription[T]) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.subs[s] {
+		delete(f.subs, s)
+		close(s.c)
+	}
+}
+
+// publish sends v to every subscriber without blocking, dropping subscribers that are full
+func (f *feed[T]) publish(v T) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for s := range f.subs {
+		select {
+		case s.c <- v:
+		default:
+			delete(f.subs, s)
+			close(s.c)
+		}
+	}
+}
+
+// SubscribeOrders returns a subscription to snapshots of orders as they are saved or updated
+func (om *OrderManager) SubscribeOrders() *Subscription[Order] {
+	return om.orderFeed.subscribe()
+}
+
+// SubscribeExecutions returns a subscription to executions as they are saved
+func (om *OrderManager) SubscribeExecutions() *Subscription[Execution] {
+	return om.executionFeed.subscribe()
+}
+
+// OrderUpdated publishes a snapshot of order to the order subscribers, the caller must hold the lock
+func (om *OrderManager) OrderUpdated(order *Order) {
+	om.orderFeed.publish(order.Snapshot())
+}
@@ -0,0 +1,58 @@
+package oms
+
+import (
+	"errors"
+
+	"github.com/quickfixgo/enum"
+	"github.com/shopspring/decimal"
+)
+
+// Leg is one instrument of a multileg order
+type Leg struct {
+	Symbol             string            `json:"symbol"`
+	SecurityType       enum.SecurityType `json:"security_type"`
+	MaturityMonthYear  string            `json:"maturity_month_year"`
+	PutOrCall          enum.PutOrCall    `json:"put_or_call"`
+	StrikePrice        string            `json:"strike_price"`
+	StrikePriceDecimal decimal.Decimal   `json:"-"`
+	Ratio              string            `json:"ratio"`
+	RatioDecimal       decimal.Decimal   `json:"-"`
+	Side               enum.Side         `json:"side"`
+}
+
+// Init initialized computed fields on leg from user input
+func (leg *Leg) Init() error {
+	var err error
+	if leg.Symbol == "" {
+		return errors.New("Invalid Leg Symbol")
+	}
+
+	if !validSide(leg.Side) {
+		return errors.New("Invalid Leg Side")
+	}
+
+	if leg.RatioDecimal, err = decimal.NewFromString(leg.Ratio); err != nil || !leg.RatioDecimal.IsPositive() {
+		return errors.New("Invalid Leg Ratio")
+	}
+
+	if leg.StrikePrice != "" {
+		if leg.StrikePriceDecimal, err = decimal.NewFromString(leg.StrikePrice); err != nil {
+			return errors.New("Invalid Leg StrikePrice")
+		}
+	}
+
+	return nil
+}
+
+// validSide is true for the sides of FIX Side(54)
+func validSide(side enum.Side) bool {
+	switch side {
+	case enum.Side_BUY, enum.Side_SELL, enum.Side_BUY_MINUS, enum.Side_SELL_PLUS, enum.Side_SELL_SHORT,
+		enum.Side_SELL_SHORT_EXEMPT, enum.Side_UNDISCLOSED, enum.Side_CROSS, enum.Side_CROSS_SHORT,
+		enum.Side_CROSS_SHORT_EXEMPT, enum.Side_AS_DEFINED, enum.Side_OPPOSITE, enum.Side_SUBSCRIBE,
+		enum.Side_REDEEM, enum.Side_LEND, enum.Side_BORROW:
+		return true
+	}
+
+	return false
+}
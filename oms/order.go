@@ -2,6 +2,7 @@ package oms
 
 import (
 	"errors"
+	"time"
 
 	"github.com/quickfixgo/enum"
 	"github.com/shopspring/decimal"
@@ -12,6 +13,7 @@ import (
 // Order is the order type
 type Order struct {
 	ID                 int                `json:"id"`
+	ParentID           int                `json:"parent_id"`
 	SessionID          quickfix.SessionID `json:"-"`
 	ClOrdID            string             `json:"clord_id"`
 	OrderID            string             `json:"order_id"`
@@ -29,6 +31,7 @@ type Order struct {
 	Closed             string             `json:"closed"`
 	Open               string             `json:"open"`
 	AvgPx              string             `json:"avg_px"`
+	OrdStatus          enum.OrdStatus     `json:"ord_status"`
 	SecurityType       enum.SecurityType  `json:"security_type"`
 	SecurityDesc       string             `json:"security_desc"`
 	MaturityMonthYear  string             `json:"maturity_month_year"`
@@ -36,6 +39,28 @@ type Order struct {
 	PutOrCall          enum.PutOrCall     `json:"put_or_call"`
 	StrikePrice        string             `json:"strike_price"`
 	StrikePriceDecimal decimal.Decimal    `json:"-"`
+	Legs               []Leg              `json:"legs"`
+	QuoteID            string             `json:"quote_id"`
+	External           bool               `json:"external"`
+	RejectReason       string             `json:"reject_reason"`
+	User               string             `json:"user"`
+	CreatedAt          time.Time          `json:"created_at"`
+}
+
+// ResetServerFields clears the fields of an order decoded from user input that only the server sets,
+// so clients cannot mark orders external, attach them to algos or forge their fills
+func (order *Order) ResetServerFields() {
+	order.ID = 0
+	order.ParentID = 0
+	order.ClOrdID = ""
+	order.OrderID = ""
+	order.Closed = ""
+	order.Open = ""
+	order.AvgPx = ""
+	order.OrdStatus = ""
+	order.External = false
+	order.RejectReason = ""
+	order.CreatedAt = time.Time{}
 }
 
 // Init initialized computed fields on order from user input
@@ -51,8 +76,18 @@ func (order *Order) Init() error {
 		}
 	}
 
+	if len(order.Legs) == 1 {
+		return errors.New("Multileg orders need at least two legs")
+	}
+
+	for i := range order.Legs {
+		if err = order.Legs[i].Init(); err != nil {
+			return err
+		}
+	}
+
 	switch order.OrdType {
-	case enum.OrdType_LIMIT, enum.OrdType_STOP_LIMIT:
+	case enum.OrdType_LIMIT, enum.OrdType_STOP_LIMIT, enum.OrdType_PREVIOUSLY_QUOTED:
 		if order.PriceDecimal, err = decimal.NewFromString(order.Price); err != nil {
 			return errors.New("Invalid Price")
 		}
@@ -65,5 +100,17 @@ func (order *Order) Init() error {
 		}
 	}
 
+	if order.OrdType == enum.OrdType_PREVIOUSLY_QUOTED && order.QuoteID == "" {
+		return errors.New("Invalid QuoteID")
+	}
+
 	return nil
 }
+
+// Snapshot returns a copy of the order that shares no state with it, for use outside of the
+// order manager lock
+func (order *Order) Snapshot() Order {
+	snapshot := *order
+	snapshot.Legs = append([]Leg(nil), order.Legs...)
+	return snapshot
+}
@@ -2,7 +2,9 @@ package oms
 
 import (
 	"fmt"
+	"sort"
 	"sync"
+	"time"
 )
 
 type ClOrdIDGenerator interface {
@@ -18,31 +20,66 @@ type OrderManager struct {
 	orders        map[int]*Order
 	clOrdIDLookup map[string]*Order
 	executions    map[int]*Execution
+	templateID    int
+	templates     map[int]*Template
+
+	// ordered indexes, each slice in ascending id order
+	orderList     []*Order
+	ordersBy      map[string]map[string][]*Order
+	executionList []*Execution
+	executionsBy  map[string]map[string][]*Execution
+
+	orderFeed     feed[Order]
+	executionFeed feed[Execution]
+}
+
+// orderIndexes are the order fields indexed for queries
+var orderIndexes = map[string]func(*Order) string{
+	"symbol":     func(o *Order) string { return o.Symbol },
+	"account":    func(o *Order) string { return o.Account },
+	"session_id": func(o *Order) string { return o.Session },
+}
+
+// executionIndexes are the execution fields indexed for queries
+var executionIndexes = map[string]func(*Execution) string{
+	"symbol":     func(e *Execution) string { return e.Symbol },
+	"session_id": func(e *Execution) string { return e.Session },
 }
 
 func NewOrderManager(idGen ClOrdIDGenerator) *OrderManager {
-	return &OrderManager{
+	om := &OrderManager{
 		clOrdIDLookup: make(map[string]*Order),
 		orders:        make(map[int]*Order),
 		executions:    make(map[int]*Execution),
+		templates:     make(map[int]*Template),
 		clOrdID:       idGen,
+		ordersBy:      make(map[string]map[string][]*Order),
+		executionsBy:  make(map[string]map[string][]*Execution),
 	}
+
+	for field := range orderIndexes {
+		om.ordersBy[field] = make(map[string][]*Order)
+	}
+
+	for field := range executionIndexes {
+		om.executionsBy[field] = make(map[string][]*Execution)
+	}
+
+	return om
 }
 
+// GetAll returns every order in id order
 func (om *OrderManager) GetAll() []*Order {
-	orders := make([]*Order, 0, len(om.clOrdIDLookup))
-	for _, v := range om.clOrdIDLookup {
-		orders = append(orders, v)
-	}
+	orders := make([]*Order, len(om.orderList))
+	copy(orders, om.orderList)
 
 	return orders
 }
 
+// GetAllExecutions returns every execution in id order
 func (om *OrderManager) GetAllExecutions() []*Execution {
-	executions := make([]*Execution, 0, len(om.executions))
-	for _, v := range om.executions {
-		executions = append(executions, v)
-	}
+	executions := make([]*Execution, len(om.executionList))
+	copy(executions, om.executionList)
 
 	return executions
 }
@@ -78,18 +115,94 @@ func (om *OrderManager) GetByClOrdID(clOrdID string) (*Order, error) {
 }
 
 func (om *OrderManager) Save(order *Order) error {
-	order.ID = om.nextOrderID()
 	order.ClOrdID = om.clOrdID.Next()
+	om.insert(order)
+
+	return nil
+}
 
+// insert assigns the order an id and adds it to the indexes
+func (om *OrderManager) insert(order *Order) {
+	order.ID = om.nextOrderID()
+	order.CreatedAt = time.Now().UTC()
+	om.index(order)
+}
+
+// index adds the order to the indexes
+func (om *OrderManager) index(order *Order) {
 	om.orders[order.ID] = order
 	om.clOrdIDLookup[order.ClOrdID] = order
+	om.orderList = insertByID(om.orderList, order)
+	for field, value := range orderIndexes {
+		om.ordersBy[field][value(order)] = insertByID(om.ordersBy[field][value(order)], order)
+	}
+
+	om.OrderUpdated(order)
+}
+
+// SaveExternal saves an order placed outside of this client, keeping its ClOrdID
+func (om *OrderManager) SaveExternal(order *Order) error {
+	if _, ok := om.clOrdIDLookup[order.ClOrdID]; ok {
+		return fmt.Errorf("order with clordid %v already exists", order.ClOrdID)
+	}
+
+	order.External = true
+	om.insert(order)
+
+	return nil
+}
+
+// insertByID inserts order into list keeping it in ascending id order, restored orders may
+// arrive out of order
+func insertByID(list []*Order, order *Order) []*Order {
+	i := sort.Search(len(list), func(i int) bool { return list[i].ID > order.ID })
+	list = append(list, nil)
+	copy(list[i+1:], list[i:])
+	list[i] = order
+
+	return list
+}
+
+// Restore adds an order recorded elsewhere, keeping its id, ClOrdID and creation time
+func (om *OrderManager) Restore(order *Order) error {
+	if _, ok := om.orders[order.ID]; ok {
+		return fmt.Errorf("order with id %v already exists", order.ID)
+	}
+	if _, ok := om.clOrdIDLookup[order.ClOrdID]; ok {
+		return fmt.Errorf("order with clordid %v already exists", order.ClOrdID)
+	}
+
+	if order.ID > om.orderID {
+		om.orderID = order.ID
+	}
+	om.index(order)
 
 	return nil
 }
 
+// AssignClOrdID links an additional ClOrdID to the order, e.g. after an external cancel/replace
+func (om *OrderManager) AssignClOrdID(order *Order, clOrdID string) {
+	om.clOrdIDLookup[clOrdID] = order
+}
+
+// UnassignClOrdID removes the link of a ClOrdID that was never accepted for the order, e.g. of a
+// rejected cancel or replace
+func (om *OrderManager) UnassignClOrdID(clOrdID string) {
+	if order, ok := om.clOrdIDLookup[clOrdID]; ok && order.ClOrdID != clOrdID {
+		delete(om.clOrdIDLookup, clOrdID)
+	}
+}
+
 func (om *OrderManager) SaveExecution(exec *Execution) error {
 	exec.ID = om.nextExecutionID()
+	exec.CreatedAt = time.Now().UTC()
+
 	om.executions[exec.ID] = exec
+	om.executionList = append(om.executionList, exec)
+	for field, value := range executionIndexes {
+		om.executionsBy[field][value(exec)] = append(om.executionsBy[field][value(exec)], exec)
+	}
+	om.executionFeed.publish(*exec)
 
 	return nil
 }
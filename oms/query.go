@@ -0,0 +1,260 @@
+package oms
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/quickfixgo/enum"
+)
+
+// OrderQuery selects a page of orders. Zero values match any order.
+type OrderQuery struct {
+	Status  []enum.OrdStatus
+	Symbol  string
+	Account string
+	Session string
+	Side    enum.Side
+	Since   time.Time
+	// Sort is an order field, prefixed with - for descending order. Orders are sorted by id by default.
+	Sort string
+	// Limit is the maximum page size, 0 for no limit
+	Limit int
+	// After is the cursor returned with the previous page
+	After int
+	// Allow additionally filters orders, e.g. by permissions
+	Allow func(*Order) bool
+}
+
+// ExecutionQuery selects a page of executions. Zero values match any execution.
+type ExecutionQuery struct {
+	Symbol  string
+	Session string
+	Side    enum.Side
+	Since   time.Time
+	// Sort is an execution field, prefixed with - for descending order. Executions are sorted by id by default.
+	Sort  string
+	Limit int
+	After int
+	Allow func(*Execution) bool
+}
+
+// orderSorts compare orders by the fields they may be sorted by
+var orderSorts = map[string]func(a, b *Order) int{
+	"created_at": func(a, b *Order) int { return a.CreatedAt.Compare(b.CreatedAt) },
+	"symbol":     func(a, b *Order) int { return strings.Compare(a.Symbol, b.Symbol) },
+	"account":    func(a, b *Order) int { return strings.Compare(a.Account, b.Account) },
+	"session_id": func(a, b *Order) int { return strings.Compare(a.Session, b.Session) },
+	"side":       func(a, b *Order) int { return strings.Compare(string(a.Side), string(b.Side)) },
+	"ord_status": func(a, b *Order) int { return strings.Compare(string(a.OrdStatus), string(b.OrdStatus)) },
+}
+
+// executionSorts compare executions by the fields they may be sorted by
+var executionSorts = map[string]func(a, b *Execution) int{
+	"created_at": func(a, b *Execution) int { return a.CreatedAt.Compare(b.CreatedAt) },
+	"symbol":     func(a, b *Execution) int { return strings.Compare(a.Symbol, b.Symbol) },
+	"session_id": func(a, b *Execution) int { return strings.Compare(a.Session, b.Session) },
+	"side":       func(a, b *Execution) int { return strings.Compare(string(a.Side), string(b.Side)) },
+}
+
+// parseSort splits a sort parameter into the field comparison and direction, a nil comparison
+// sorts by id
+func parseSort[T any](sortBy string, sorts map[string]func(a, b T) int) (cmp func(a, b T) int, desc bool, err error) {
+	desc = strings.HasPrefix(sortBy, "-")
+	field := strings.TrimPrefix(sortBy, "-")
+	if field == "" || field == "id" {
+		return nil, desc, nil
+	}
+
+	cmp, ok := sorts[field]
+	if !ok {
+		return nil, false, fmt.Errorf("cannot sort by %v", field)
+	}
+
+	return cmp, desc, nil
+}
+
+// pager pages through candidates held in ascending id order
+type pager[T any] struct {
+	id     func(T) int
+	match  func(T) bool
+	cmp    func(a, b T) int
+	desc   bool
+	limit  int
+	after  int
+	lookup func(id int) (T, bool)
+}
+
+// less orders items by the sort field, then by id
+func (p pager[T]) less(a, b T) bool {
+	c := 0
+	if p.cmp != nil {
+		c = p.cmp(a, b)
+	}
+
+	if c == 0 {
+		c = p.id(a) - p.id(b)
+	}
+
+	if p.desc {
+		return c > 0
+	}
+
+	return c < 0
+}
+
+func (p pager[T]) full(items []T) bool {
+	return p.limit > 0 && len(items) > p.limit
+}
+
+// page returns the matching items after the cursor, and the cursor of the next page or 0 if
+// this is the last page
+func (p pager[T]) page(candidates []T) ([]T, int) {
+	items := make([]T, 0)
+
+	switch {
+	case p.cmp == nil && !p.desc:
+		start := sort.Search(len(candidates), func(i int) bool { return p.id(candidates[i]) > p.after })
+		for i := start; i < len(candidates) && !p.full(items); i++ {
+			if p.match(candidates[i]) {
+				items = append(items, candidates[i])
+			}
+		}
+
+	case p.cmp == nil:
+		end := len(candidates)
+		if p.after > 0 {
+			end = sort.Search(len(candidates), func(i int) bool { return p.id(candidates[i]) >= p.after })
+		}
+		for i := end - 1; i >= 0 && !p.full(items); i-- {
+			if p.match(candidates[i]) {
+				items = append(items, candidates[i])
+			}
+		}
+
+	default:
+		for _, c := range candidates {
+			if p.match(c) {
+				items = append(items, c)
+			}
+		}
+		sort.Slice(items, func(i, j int) bool { return p.less(items[i], items[j]) })
+
+		if cursor, ok := p.lookup(p.after); ok && p.after > 0 {
+			items = items[sort.Search(len(items), func(i int) bool { return p.less(cursor, items[i]) }):]
+		}
+	}
+
+	if !p.full(items) {
+		return items, 0
+	}
+
+	items = items[:p.limit]
+	return items, p.id(items[len(items)-1])
+}
+
+// QueryOrders returns a page of the orders matching q, and the cursor of the next page or 0 if
+// there are no more
+func (om *OrderManager) QueryOrders(q OrderQuery) ([]*Order, int, error) {
+	cmp, desc, err := parseSort(q.Sort, orderSorts)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// start from the narrowest index
+	candidates := om.orderList
+	for field, key := range map[string]string{"symbol": q.Symbol, "account": q.Account, "session_id": q.Session} {
+		if key != "" && len(om.ordersBy[field][key]) < len(candidates) {
+			candidates = om.ordersBy[field][key]
+		}
+	}
+
+	match := func(o *Order) bool {
+		switch {
+		case q.Symbol != "" && o.Symbol != q.Symbol:
+			return false
+		case q.Account != "" && o.Account != q.Account:
+			return false
+		case q.Session != "" && o.Session != q.Session:
+			return false
+		case q.Side != "" && o.Side != q.Side:
+			return false
+		case !q.Since.IsZero() && o.CreatedAt.Before(q.Since):
+			return false
+		case q.Allow != nil && !q.Allow(o):
+			return false
+		}
+
+		if len(q.Status) == 0 {
+			return true
+		}
+
+		for _, status := range q.Status {
+			if o.OrdStatus == status {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	p := pager[*Order]{
+		id:     func(o *Order) int { return o.ID },
+		match:  match,
+		cmp:    cmp,
+		desc:   desc,
+		limit:  q.Limit,
+		after:  q.After,
+		lookup: func(id int) (*Order, bool) { o, ok := om.orders[id]; return o, ok },
+	}
+
+	orders, next := p.page(candidates)
+	return orders, next, nil
+}
+
+// QueryExecutions returns a page of the executions matching q, and the cursor of the next page
+// or 0 if there are no more
+func (om *OrderManager) QueryExecutions(q ExecutionQuery) ([]*Execution, int, error) {
+	cmp, desc, err := parseSort(q.Sort, executionSorts)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	candidates := om.executionList
+	for field, key := range map[string]string{"symbol": q.Symbol, "session_id": q.Session} {
+		if key != "" && len(om.executionsBy[field][key]) < len(candidates) {
+			candidates = om.executionsBy[field][key]
+		}
+	}
+
+	match := func(e *Execution) bool {
+		switch {
+		case q.Symbol != "" && e.Symbol != q.Symbol:
+			return false
+		case q.Session != "" && e.Session != q.Session:
+			return false
+		case q.Side != "" && e.Side != q.Side:
+			return false
+		case !q.Since.IsZero() && e.CreatedAt.Before(q.Since):
+			return false
+		case q.Allow != nil && !q.Allow(e):
+			return false
+		}
+
+		return true
+	}
+
+	p := pager[*Execution]{
+		id:     func(e *Execution) int { return e.ID },
+		match:  match,
+		cmp:    cmp,
+		desc:   desc,
+		limit:  q.Limit,
+		after:  q.After,
+		lookup: func(id int) (*Execution, bool) { e, ok := om.executions[id]; return e, ok },
+	}
+
+	executions, next := p.page(candidates)
+	return executions, next, nil
+}
@@ -0,0 +1,104 @@
+package oms
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Template is a named ticket a user submits again and again, holding the order fields a trader
+// enters. Fields left empty are filled in on submit.
+type Template struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	Order     Order     `json:"order"`
+	User      string    `json:"user"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Init checks the template and clears the order of anything but its ticket
+func (t *Template) Init() error {
+	if t.Name == "" {
+		return errors.New("template name is required")
+	}
+
+	t.Order = t.Order.Ticket()
+	return nil
+}
+
+// Ticket returns the order fields entered by a trader, without ids and state
+func (order Order) Ticket() Order {
+	return Order{
+		Symbol:            order.Symbol,
+		Quantity:          order.Quantity,
+		Account:           order.Account,
+		Session:           order.Session,
+		Side:              order.Side,
+		OrdType:           order.OrdType,
+		Price:             order.Price,
+		StopPrice:         order.StopPrice,
+		SecurityType:      order.SecurityType,
+		SecurityDesc:      order.SecurityDesc,
+		MaturityMonthYear: order.MaturityMonthYear,
+		MaturityDay:       order.MaturityDay,
+		PutOrCall:         order.PutOrCall,
+		StrikePrice:       order.StrikePrice,
+		Legs:              order.Legs,
+	}
+}
+
+// SaveTemplate assigns the template an id
+func (om *OrderManager) SaveTemplate(t *Template) error {
+	om.templateID++
+	t.ID = om.templateID
+	t.CreatedAt = time.Now().UTC()
+	t.UpdatedAt = t.CreatedAt
+	om.templates[t.ID] = t
+
+	return nil
+}
+
+// GetTemplate returns the template with id
+func (om *OrderManager) GetTemplate(id int) (*Template, error) {
+	t, ok := om.templates[id]
+	if !ok {
+		return nil, fmt.Errorf("could not find template with id %v", id)
+	}
+
+	return t, nil
+}
+
+// GetTemplates returns every template in id order
+func (om *OrderManager) GetTemplates() []*Template {
+	templates := make([]*Template, 0, len(om.templates))
+	for id := 1; id <= om.templateID; id++ {
+		if t, ok := om.templates[id]; ok {
+			templates = append(templates, t)
+		}
+	}
+
+	return templates
+}
+
+// UpdateTemplate replaces the name and order of the template with id, keeping its owner
+func (om *OrderManager) UpdateTemplate(id int, update Template) (*Template, error) {
+	t, err := om.GetTemplate(id)
+	if err != nil {
+		return nil, err
+	}
+
+	t.Name, t.Order = update.Name, update.Order
+	t.UpdatedAt = time.Now().UTC()
+	return t, nil
+}
+
+// DeleteTemplate removes the template with id
+func (om *OrderManager) DeleteTemplate(id int) error {
+	if _, err := om.GetTemplate(id); err != nil {
+		return err
+	}
+
+	delete(om.templates, id)
+	return nil
+}
@@ -0,0 +1,130 @@
+package paper
+
+import (
+	"sort"
+
+	"github.com/quickfixgo/enum"
+	"github.com/shopspring/decimal"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+// order is an order of a paper session as the engine sees it
+type order struct {
+	orderID  string
+	clOrdID  string
+	symbol   string
+	side     enum.Side
+	ordType  enum.OrdType
+	price    decimal.Decimal
+	quantity decimal.Decimal
+	cumQty   decimal.Decimal
+	notional decimal.Decimal
+	status   enum.OrdStatus
+	// seq orders resting orders of the same price by time
+	seq int
+}
+
+func (o *order) leavesQty() decimal.Decimal {
+	if o.done() {
+		return decimal.Zero
+	}
+
+	return o.quantity.Sub(o.cumQty)
+}
+
+func (o *order) avgPx() decimal.Decimal {
+	if o.cumQty.IsZero() {
+		return decimal.Zero
+	}
+
+	return o.notional.Div(o.cumQty)
+}
+
+func (o *order) done() bool {
+	switch o.status {
+	case enum.OrdStatus_FILLED, enum.OrdStatus_CANCELED, enum.OrdStatus_REJECTED:
+		return true
+	}
+
+	return false
+}
+
+func (o *order) buy() bool {
+	return o.side == enum.Side_BUY || o.side == enum.Side_BUY_MINUS
+}
+
+// fill executes qty of the order at px
+func (o *order) fill(qty, px decimal.Decimal) {
+	o.cumQty = o.cumQty.Add(qty)
+	o.notional = o.notional.Add(qty.Mul(px))
+	if o.cumQty.Equal(o.quantity) {
+		o.status = enum.OrdStatus_FILLED
+	} else {
+		o.status = enum.OrdStatus_PARTIALLY_FILLED
+	}
+}
+
+// crosses is true if the order may trade at px, the price of a resting order or the reference price
+func (o *order) crosses(px decimal.Decimal) bool {
+	if o.ordType == enum.OrdType_MARKET {
+		return true
+	}
+
+	if o.buy() {
+		return o.price.GreaterThanOrEqual(px)
+	}
+	return o.price.LessThanOrEqual(px)
+}
+
+// bookKey identifies the book of a symbol on a session
+type bookKey struct {
+	sessionID quickfix.SessionID
+	symbol    string
+}
+
+// book holds the resting limit orders of a symbol, best price and then earliest first
+type book struct {
+	bids, asks []*order
+}
+
+func (b *book) side(buy bool) *[]*order {
+	if buy {
+		return &b.bids
+	}
+	return &b.asks
+}
+
+// add rests the order on its side of the book
+func (b *book) add(o *order) {
+	orders := b.side(o.buy())
+	*orders = append(*orders, o)
+	sort.SliceStable(*orders, func(i, j int) bool {
+		a, c := (*orders)[i], (*orders)[j]
+		if !a.price.Equal(c.price) {
+			return a.price.GreaterThan(c.price) == o.buy()
+		}
+		return a.seq < c.seq
+	})
+}
+
+// remove takes the order off the book
+func (b *book) remove(o *order) {
+	orders := b.side(o.buy())
+	for i, resting := range *orders {
+		if resting == o {
+			*orders = append((*orders)[:i], (*orders)[i+1:]...)
+			return
+		}
+	}
+}
+
+// best returns the best order resting against an order of side buy, nil if there is none
+func (b *book) best(buy bool) *order {
+	orders := *b.side(!buy)
+	if len(orders) == 0 {
+		return nil
+	}
+
+	return orders[0]
+}
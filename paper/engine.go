@@ -0,0 +1,383 @@
+// Package paper simulates the counterparty of paper sessions in process, so traders can be
+// trained without a FIX connection.
+package paper
+
+import (
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/quickfixgo/enum"
+	"github.com/quickfixgo/field"
+	"github.com/quickfixgo/tag"
+	"github.com/shopspring/decimal"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+// Engine matches the orders of each paper session against each other, symbol by symbol with
+// price and time priority, and reports on them to a quickfix.Application as the counterparty
+// would. What the book cannot fill trades at the reference price of the symbol if the order is
+// marketable at it. Limit orders rest until they trade, market orders trade what they can and the
+// rest is cancelled. Reports are delivered before Send returns.
+type Engine struct {
+	// ReferencePrice returns the price a symbol trades at on a session when the book is exhausted,
+	// ok is false if it has none. Orders only trade against each other if nil.
+	ReferencePrice func(sessionID quickfix.SessionID, symbol string) (px decimal.Decimal, ok bool)
+
+	// LogFactory creates the logs of paper sessions, which get the messages sent to and reported
+	// by the engine as quickfix logs those of other sessions. Nothing is logged if nil.
+	LogFactory quickfix.LogFactory
+
+	mu   sync.Mutex
+	app  quickfix.Application
+	logs map[quickfix.SessionID]quickfix.Log
+
+	books   map[bookKey]*book
+	orders  map[string]*order
+	orderID int
+	execID  int
+	seq     int
+}
+
+// NewEngine returns an engine reporting to app
+func NewEngine(app quickfix.Application) *Engine {
+	return &Engine{
+		app:    app,
+		logs:   make(map[quickfix.SessionID]quickfix.Log),
+		books:  make(map[bookKey]*book),
+		orders: make(map[string]*order),
+	}
+}
+
+// Logon creates the session and its log on the application and logs it on, as the initiator
+// does for sessions with a counterparty
+func (e *Engine) Logon(sessionID quickfix.SessionID) error {
+	if e.LogFactory != nil {
+		l, err := e.LogFactory.CreateSessionLog(sessionID)
+		if err != nil {
+			return err
+		}
+
+		e.mu.Lock()
+		e.logs[sessionID] = l
+		e.mu.Unlock()
+		l.OnEvent("Logged on to the paper engine")
+	}
+
+	e.app.OnCreate(sessionID)
+	e.app.OnLogon(sessionID)
+	return nil
+}
+
+// logMessage writes msg to the log of the session, if it has one
+func (e *Engine) logMessage(sessionID quickfix.SessionID, msg *quickfix.Message, incoming bool) {
+	l, ok := e.logs[sessionID]
+	if !ok {
+		return
+	}
+
+	if incoming {
+		l.OnIncoming([]byte(msg.String()))
+	} else {
+		l.OnOutgoing([]byte(msg.String()))
+	}
+}
+
+// Send handles a NewOrderSingle, OrderCancelRequest or OrderCancelReplaceRequest of a paper
+// session. Cancels and replaces of unknown or closed orders fail.
+func (e *Engine) Send(m quickfix.Messagable, sessionID quickfix.SessionID) error {
+	msg := m.ToMessage()
+	msgType, err := msg.MsgType()
+	if err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var handle func(*quickfix.Message, quickfix.SessionID) error
+	switch enum.MsgType(msgType) {
+	case enum.MsgType_ORDER_SINGLE:
+		handle = e.newOrder
+	case enum.MsgType_ORDER_CANCEL_REQUEST:
+		handle = e.cancel
+	case enum.MsgType_ORDER_CANCEL_REPLACE_REQUEST:
+		handle = e.replace
+	default:
+		return fmt.Errorf("paper sessions do not support MsgType %v", msgType)
+	}
+
+	e.logMessage(sessionID, msg, false)
+	return handle(msg, sessionID)
+}
+
+func orderKey(sessionID quickfix.SessionID, clOrdID string) string {
+	return sessionID.String() + " " + clOrdID
+}
+
+func (e *Engine) book(sessionID quickfix.SessionID, symbol string) *book {
+	key := bookKey{sessionID, symbol}
+	b, ok := e.books[key]
+	if !ok {
+		b = new(book)
+		e.books[key] = b
+	}
+
+	return b
+}
+
+func (e *Engine) newOrder(msg *quickfix.Message, sessionID quickfix.SessionID) error {
+	var clOrdID field.ClOrdIDField
+	if err := msg.Body.Get(&clOrdID); err != nil {
+		return err
+	}
+
+	var side field.SideField
+	if err := msg.Body.Get(&side); err != nil {
+		return err
+	}
+
+	var ordType field.OrdTypeField
+	if err := msg.Body.Get(&ordType); err != nil {
+		return err
+	}
+
+	var orderQty field.OrderQtyField
+	if err := msg.Body.Get(&orderQty); err != nil {
+		return err
+	}
+
+	e.orderID++
+	e.seq++
+	o := &order{
+		orderID:  fmt.Sprintf("PAPER-%v", e.orderID),
+		clOrdID:  clOrdID.String(),
+		side:     side.Value(),
+		ordType:  ordType.Value(),
+		quantity: orderQty.Value(),
+		status:   enum.OrdStatus_NEW,
+		seq:      e.seq,
+	}
+	o.symbol, _ = msg.Body.GetString(tag.Symbol)
+	e.orders[orderKey(sessionID, o.clOrdID)] = o
+
+	switch {
+	case o.ordType != enum.OrdType_MARKET && o.ordType != enum.OrdType_LIMIT:
+		e.reject(sessionID, o, fmt.Sprintf("paper sessions do not support OrdType %v", o.ordType))
+		return nil
+	case !o.quantity.IsPositive():
+		e.reject(sessionID, o, "quantity must be positive")
+		return nil
+	}
+
+	if o.ordType == enum.OrdType_LIMIT {
+		var price field.PriceField
+		if err := msg.Body.Get(&price); err != nil {
+			e.reject(sessionID, o, "limit orders need a price")
+			return nil
+		}
+		o.price = price.Value()
+	}
+
+	e.report(sessionID, o, enum.ExecType_NEW, nil, "", "")
+	e.match(sessionID, o)
+	return nil
+}
+
+// match trades the order against the book of its symbol and then at the reference price, resting
+// what is left of a limit order and cancelling what is left of a market order
+func (e *Engine) match(sessionID quickfix.SessionID, o *order) {
+	b := e.book(sessionID, o.symbol)
+	for !o.done() {
+		resting := b.best(o.buy())
+		if resting == nil || !o.crosses(resting.price) {
+			break
+		}
+
+		qty := decimal.Min(o.leavesQty(), resting.leavesQty())
+		px := resting.price
+		resting.fill(qty, px)
+		o.fill(qty, px)
+		if resting.done() {
+			b.remove(resting)
+		}
+
+		e.report(sessionID, resting, fillExecType(sessionID, resting), &execution{qty, px}, "", "")
+		e.report(sessionID, o, fillExecType(sessionID, o), &execution{qty, px}, "", "")
+	}
+
+	if px, ok := e.referencePrice(sessionID, o.symbol); ok && !o.done() && o.crosses(px) {
+		qty := o.leavesQty()
+		o.fill(qty, px)
+		e.report(sessionID, o, fillExecType(sessionID, o), &execution{qty, px}, "", "")
+	}
+
+	switch {
+	case o.done():
+	case o.ordType == enum.OrdType_MARKET:
+		o.status = enum.OrdStatus_CANCELED
+		e.report(sessionID, o, enum.ExecType_CANCELED, nil, "", "no liquidity")
+	default:
+		b.add(o)
+	}
+}
+
+func (e *Engine) referencePrice(sessionID quickfix.SessionID, symbol string) (decimal.Decimal, bool) {
+	if e.ReferencePrice == nil {
+		return decimal.Zero, false
+	}
+
+	return e.ReferencePrice(sessionID, symbol)
+}
+
+// working returns the open order of a cancel or replace request
+func (e *Engine) working(msg *quickfix.Message, sessionID quickfix.SessionID) (*order, string, error) {
+	var origClOrdID field.OrigClOrdIDField
+	if err := msg.Body.Get(&origClOrdID); err != nil {
+		return nil, "", err
+	}
+
+	var clOrdID field.ClOrdIDField
+	if err := msg.Body.Get(&clOrdID); err != nil {
+		return nil, "", err
+	}
+
+	o, ok := e.orders[orderKey(sessionID, origClOrdID.String())]
+	if !ok {
+		return nil, "", fmt.Errorf("unknown order %v", origClOrdID)
+	}
+	if o.done() {
+		return nil, "", fmt.Errorf("order %v is %v", origClOrdID, o.status)
+	}
+
+	return o, clOrdID.String(), nil
+}
+
+func (e *Engine) cancel(msg *quickfix.Message, sessionID quickfix.SessionID) error {
+	o, clOrdID, err := e.working(msg, sessionID)
+	if err != nil {
+		return err
+	}
+
+	e.book(sessionID, o.symbol).remove(o)
+	origClOrdID := o.clOrdID
+	e.rename(sessionID, o, clOrdID)
+	o.status = enum.OrdStatus_CANCELED
+	e.report(sessionID, o, enum.ExecType_CANCELED, nil, origClOrdID, "")
+
+	return nil
+}
+
+func (e *Engine) replace(msg *quickfix.Message, sessionID quickfix.SessionID) error {
+	o, clOrdID, err := e.working(msg, sessionID)
+	if err != nil {
+		return err
+	}
+
+	quantity, price := o.quantity, o.price
+	if msg.Body.Has(tag.OrderQty) {
+		var orderQty field.OrderQtyField
+		if err := msg.Body.Get(&orderQty); err != nil {
+			return err
+		}
+		quantity = orderQty.Value()
+	}
+	if msg.Body.Has(tag.Price) {
+		var px field.PriceField
+		if err := msg.Body.Get(&px); err != nil {
+			return err
+		}
+		price = px.Value()
+	}
+
+	if quantity.LessThanOrEqual(o.cumQty) {
+		return fmt.Errorf("cannot reduce order %v to %v, %v already filled", o.clOrdID, quantity, o.cumQty)
+	}
+
+	b := e.book(sessionID, o.symbol)
+	b.remove(o)
+	origClOrdID := o.clOrdID
+	e.rename(sessionID, o, clOrdID)
+	e.seq++
+	o.quantity, o.price, o.seq = quantity, price, e.seq
+	e.report(sessionID, o, enum.ExecType_REPLACED, nil, origClOrdID, "")
+
+	e.match(sessionID, o)
+	return nil
+}
+
+// rename moves the order to the ClOrdID of an accepted cancel or replace
+func (e *Engine) rename(sessionID quickfix.SessionID, o *order, clOrdID string) {
+	e.orders[orderKey(sessionID, clOrdID)] = o
+	o.clOrdID = clOrdID
+}
+
+func (e *Engine) reject(sessionID quickfix.SessionID, o *order, text string) {
+	o.status = enum.OrdStatus_REJECTED
+	e.report(sessionID, o, enum.ExecType_REJECTED, nil, "", text)
+}
+
+// execution is a trade reported on
+type execution struct {
+	qty, px decimal.Decimal
+}
+
+// fillExecType is the ExecType of a trade in the FIX version of the session
+func fillExecType(sessionID quickfix.SessionID, o *order) enum.ExecType {
+	switch sessionID.BeginString {
+	case quickfix.BeginStringFIX40, quickfix.BeginStringFIX41, quickfix.BeginStringFIX42:
+		if o.status == enum.OrdStatus_FILLED {
+			return enum.ExecType_FILL
+		}
+		return enum.ExecType_PARTIAL_FILL
+	}
+
+	return enum.ExecType_TRADE
+}
+
+// report delivers an execution report on the order to the application, of the trade exec if set
+func (e *Engine) report(sessionID quickfix.SessionID, o *order, execType enum.ExecType, exec *execution, origClOrdID, text string) {
+	e.execID++
+
+	msg := quickfix.NewMessage()
+	msg.Header.SetString(tag.BeginString, sessionID.BeginString)
+	msg.Header.SetString(tag.MsgType, string(enum.MsgType_EXECUTION_REPORT))
+	msg.Header.SetString(tag.SenderCompID, sessionID.TargetCompID)
+	msg.Header.SetString(tag.TargetCompID, sessionID.SenderCompID)
+
+	msg.Body.SetString(tag.OrderID, o.orderID)
+	msg.Body.SetString(tag.ExecID, fmt.Sprintf("PAPER-EXEC-%v", e.execID))
+	msg.Body.SetString(tag.ClOrdID, o.clOrdID)
+	if origClOrdID != "" {
+		msg.Body.SetString(tag.OrigClOrdID, origClOrdID)
+	}
+	switch sessionID.BeginString {
+	case quickfix.BeginStringFIX40, quickfix.BeginStringFIX41, quickfix.BeginStringFIX42:
+		msg.Body.SetString(tag.ExecTransType, string(enum.ExecTransType_NEW))
+	}
+	msg.Body.SetString(tag.ExecType, string(execType))
+	msg.Body.SetString(tag.OrdStatus, string(o.status))
+	msg.Body.SetString(tag.Symbol, o.symbol)
+	msg.Body.SetString(tag.Side, string(o.side))
+	msg.Body.SetString(tag.OrdType, string(o.ordType))
+	msg.Body.SetString(tag.OrderQty, o.quantity.String())
+	if o.ordType == enum.OrdType_LIMIT {
+		msg.Body.SetString(tag.Price, o.price.String())
+	}
+	msg.Body.SetString(tag.CumQty, o.cumQty.String())
+	msg.Body.SetString(tag.LeavesQty, o.leavesQty().String())
+	msg.Body.SetString(tag.AvgPx, o.avgPx().String())
+	if exec != nil {
+		msg.Body.SetString(tag.LastShares, exec.qty.String())
+		msg.Body.SetString(tag.LastPx, exec.px.String())
+	}
+	if text != "" {
+		msg.Body.SetString(tag.Text, text)
+	}
+
+	e.logMessage(sessionID, msg, true)
+	if reject := e.app.FromApp(msg, sessionID); reject != nil {
+		log.Printf("[WARN] paper: %v rejected execution report: %v", sessionID, reject)
+	}
+}
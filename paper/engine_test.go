@@ -0,0 +1,230 @@
+package paper
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/quickfixgo/enum"
+	"github.com/quickfixgo/quickfix"
+	"github.com/shopspring/decimal"
+
+	"github.com/quickfixgo/traderui/basic"
+	"github.com/quickfixgo/traderui/oms"
+)
+
+var testSessionID = quickfix.SessionID{BeginString: quickfix.BeginStringFIX42, SenderCompID: "TW", TargetCompID: "PAPER"}
+
+func newTestEngine(t *testing.T) (*Engine, *basic.FIXApplication) {
+	t.Helper()
+
+	app := &basic.FIXApplication{
+		SessionIDs:   make(map[string]quickfix.SessionID),
+		OrderManager: oms.NewOrderManager(new(basic.ClOrdIDGenerator)),
+	}
+	e := NewEngine(app)
+	if err := e.Logon(testSessionID); err != nil {
+		t.Fatal(err)
+	}
+
+	return e, app
+}
+
+// testLog keeps the MsgTypes of the messages of a session, implements quickfix.Log and
+// quickfix.LogFactory
+type testLog struct {
+	incoming, outgoing []string
+}
+
+func (l *testLog) OnIncoming(msg []byte)           { l.incoming = append(l.incoming, msgType(msg)) }
+func (l *testLog) OnOutgoing(msg []byte)           { l.outgoing = append(l.outgoing, msgType(msg)) }
+func (l *testLog) OnEvent(string)                  {}
+func (l *testLog) OnEventf(string, ...interface{}) {}
+
+func (l *testLog) Create() (quickfix.Log, error)                             { return l, nil }
+func (l *testLog) CreateSessionLog(quickfix.SessionID) (quickfix.Log, error) { return l, nil }
+
+func msgType(raw []byte) string {
+	for _, f := range strings.Split(string(raw), "\x01") {
+		if strings.HasPrefix(f, "35=") {
+			return strings.TrimPrefix(f, "35=")
+		}
+	}
+
+	return ""
+}
+
+// send saves an order of side, quantity and price, a market order if price is empty, and sends it
+func send(t *testing.T, e *Engine, app *basic.FIXApplication, side enum.Side, quantity, price string) *oms.Order {
+	t.Helper()
+
+	order := &oms.Order{
+		Symbol:    "IBM",
+		Side:      side,
+		Quantity:  quantity,
+		OrdType:   enum.OrdType_MARKET,
+		Price:     price,
+		SessionID: testSessionID,
+		Session:   testSessionID.String(),
+	}
+	if price != "" {
+		order.OrdType = enum.OrdType_LIMIT
+	}
+	if err := order.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	app.Lock()
+	_ = app.Save(order)
+	msg, err := basic.FIXFactory{}.NewOrderSingle(*order)
+	app.Unlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := e.Send(msg, testSessionID); err != nil {
+		t.Fatal(err)
+	}
+
+	return order
+}
+
+func assertOrder(t *testing.T, order *oms.Order, status enum.OrdStatus, closed, open, avgPx string) {
+	t.Helper()
+
+	if order.OrdStatus != status || order.Closed != closed || order.Open != open || order.AvgPx != avgPx {
+		t.Errorf("order %v: got status %v closed %v open %v avg px %v, want %v %v %v %v", order.ID,
+			order.OrdStatus, order.Closed, order.Open, order.AvgPx, status, closed, open, avgPx)
+	}
+}
+
+func TestEngineMatching(t *testing.T) {
+	e, app := newTestEngine(t)
+
+	if status := app.SessionStatuses(); len(status) != 1 || !status[0].LoggedOn {
+		t.Errorf("got statuses %+v, want the paper session logged on", status)
+	}
+
+	bid := send(t, e, app, enum.Side_BUY, "100", "10")
+	betterBid := send(t, e, app, enum.Side_BUY, "50", "10.5")
+	assertOrder(t, bid, enum.OrdStatus_NEW, "0", "100", "0")
+
+	sell := send(t, e, app, enum.Side_SELL, "80", "10")
+	assertOrder(t, betterBid, enum.OrdStatus_FILLED, "50", "0", "10.5")
+	assertOrder(t, bid, enum.OrdStatus_PARTIALLY_FILLED, "30", "70", "10")
+	assertOrder(t, sell, enum.OrdStatus_FILLED, "80", "0", "10.3125")
+
+	market := send(t, e, app, enum.Side_SELL, "100", "")
+	assertOrder(t, bid, enum.OrdStatus_FILLED, "100", "0", "10")
+	assertOrder(t, market, enum.OrdStatus_CANCELED, "70", "0", "10")
+
+	if n := len(app.GetAllExecutions()); n != 6 {
+		t.Errorf("got %v executions, want 6", n)
+	}
+}
+
+func TestEngineReferencePrice(t *testing.T) {
+	e, app := newTestEngine(t)
+	e.ReferencePrice = func(sessionID quickfix.SessionID, symbol string) (decimal.Decimal, bool) {
+		return decimal.NewFromInt(10), sessionID == testSessionID && symbol == "IBM"
+	}
+
+	market := send(t, e, app, enum.Side_BUY, "100", "")
+	assertOrder(t, market, enum.OrdStatus_FILLED, "100", "0", "10")
+
+	bid := send(t, e, app, enum.Side_BUY, "100", "9")
+	assertOrder(t, bid, enum.OrdStatus_NEW, "0", "100", "0")
+
+	offer := send(t, e, app, enum.Side_SELL, "150", "9")
+	assertOrder(t, bid, enum.OrdStatus_FILLED, "100", "0", "9")
+	assertOrder(t, offer, enum.OrdStatus_FILLED, "150", "0", "9.3333333333333333")
+
+	if n := len(app.GetAllExecutions()); n != 4 {
+		t.Errorf("got %v executions, want the book traded before the reference price", n)
+	}
+}
+
+func TestEngineCancelReplace(t *testing.T) {
+	e, app := newTestEngine(t)
+	order := send(t, e, app, enum.Side_BUY, "100", "10")
+
+	app.Lock()
+	amended := *order
+	amended.Quantity, amended.Price = "200", "11"
+	if err := amended.Init(); err != nil {
+		t.Fatal(err)
+	}
+	replaceClOrdID := app.AssignNextClOrdID(order)
+	replace, err := basic.FIXFactory{}.OrderCancelReplaceRequest(amended, replaceClOrdID)
+	app.Unlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = e.Send(replace, testSessionID); err != nil {
+		t.Fatal(err)
+	}
+	if order.ClOrdID != replaceClOrdID || order.Quantity != "200" || order.Price != "11" {
+		t.Errorf("got clordid %v quantity %v price %v, want the order replaced", order.ClOrdID, order.Quantity, order.Price)
+	}
+
+	send(t, e, app, enum.Side_SELL, "50", "11")
+	assertOrder(t, order, enum.OrdStatus_PARTIALLY_FILLED, "50", "150", "11")
+
+	app.Lock()
+	cancel, err := basic.FIXFactory{}.OrderCancelRequest(*order, app.AssignNextClOrdID(order))
+	app.Unlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = e.Send(cancel, testSessionID); err != nil {
+		t.Fatal(err)
+	}
+	assertOrder(t, order, enum.OrdStatus_CANCELED, "50", "0", "11")
+
+	if err = e.Send(cancel, testSessionID); err == nil {
+		t.Error("expected an error cancelling a cancelled order")
+	}
+}
+
+func TestEngineRejects(t *testing.T) {
+	e, app := newTestEngine(t)
+
+	order := &oms.Order{Symbol: "IBM", Side: enum.Side_BUY, Quantity: "100", OrdType: enum.OrdType_STOP, StopPrice: "10", SessionID: testSessionID}
+	if err := order.Init(); err != nil {
+		t.Fatal(err)
+	}
+	app.Lock()
+	_ = app.Save(order)
+	app.Unlock()
+
+	msg, err := basic.FIXFactory{}.NewOrderSingle(*order)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = e.Send(msg, testSessionID); err != nil {
+		t.Fatal(err)
+	}
+	assertOrder(t, order, enum.OrdStatus_REJECTED, "0", "0", "0")
+}
+
+func TestEngineLogs(t *testing.T) {
+	app := &basic.FIXApplication{
+		SessionIDs:   make(map[string]quickfix.SessionID),
+		OrderManager: oms.NewOrderManager(new(basic.ClOrdIDGenerator)),
+	}
+	sessionLog := new(testLog)
+	e := NewEngine(app)
+	e.LogFactory = sessionLog
+	if err := e.Logon(testSessionID); err != nil {
+		t.Fatal(err)
+	}
+
+	send(t, e, app, enum.Side_BUY, "100", "10")
+	send(t, e, app, enum.Side_SELL, "100", "10")
+
+	if got := strings.Join(sessionLog.outgoing, " "); got != "D D" {
+		t.Errorf("logged %v sent, want D D", got)
+	}
+	if got := strings.Join(sessionLog.incoming, " "); got != "8 8 8 8" {
+		t.Errorf("logged %v reported, want the acks and fills of both orders", got)
+	}
+}
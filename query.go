@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/quickfixgo/enum"
+	"github.com/quickfixgo/traderui/oms"
+)
+
+// NextCursorHeader carries the cursor of the next page of a list, absent on the last page
+const NextCursorHeader = "X-Next-Cursor"
+
+// pageParams are the paging parameters shared by list queries
+type pageParams struct {
+	since time.Time
+	sort  string
+	limit int
+	after int
+}
+
+func parsePageParams(query url.Values) (p pageParams, err error) {
+	p.sort = query.Get("sort")
+
+	if since := query.Get("since"); since != "" {
+		if p.since, err = time.Parse(time.RFC3339, since); err != nil {
+			return p, fmt.Errorf("invalid since: %v", err)
+		}
+	}
+
+	if limit := query.Get("limit"); limit != "" {
+		if p.limit, err = strconv.Atoi(limit); err != nil || p.limit < 0 {
+			return p, fmt.Errorf("invalid limit: %v", limit)
+		}
+	}
+
+	if cursor := query.Get("cursor"); cursor != "" {
+		if p.after, err = strconv.Atoi(cursor); err != nil || p.after < 0 {
+			return p, fmt.Errorf("invalid cursor: %v", cursor)
+		}
+	}
+
+	return p, nil
+}
+
+// parseOrderQuery reads the order filters status (comma separated), symbol, account, session_id,
+// side and since (RFC 3339), the sort field and the limit and cursor of the page
+func parseOrderQuery(r *http.Request) (oms.OrderQuery, error) {
+	query := r.URL.Query()
+	p, err := parsePageParams(query)
+	if err != nil {
+		return oms.OrderQuery{}, err
+	}
+
+	q := oms.OrderQuery{
+		Symbol:  query.Get("symbol"),
+		Account: query.Get("account"),
+		Session: query.Get("session_id"),
+		Side:    enum.Side(query.Get("side")),
+		Since:   p.since,
+		Sort:    p.sort,
+		Limit:   p.limit,
+		After:   p.after,
+	}
+
+	if status := query.Get("status"); status != "" {
+		for _, s := range strings.Split(status, ",") {
+			q.Status = append(q.Status, enum.OrdStatus(s))
+		}
+	}
+
+	return q, nil
+}
+
+// parseExecutionQuery reads the execution filters symbol, session_id, side and since (RFC 3339),
+// the sort field and the limit and cursor of the page
+func parseExecutionQuery(r *http.Request) (oms.ExecutionQuery, error) {
+	query := r.URL.Query()
+	p, err := parsePageParams(query)
+	if err != nil {
+		return oms.ExecutionQuery{}, err
+	}
+
+	return oms.ExecutionQuery{
+		Symbol:  query.Get("symbol"),
+		Session: query.Get("session_id"),
+		Side:    enum.Side(query.Get("side")),
+		Since:   p.since,
+		Sort:    p.sort,
+		Limit:   p.limit,
+		After:   p.after,
+	}, nil
+}
+
+// writePageJSON writes a page of a list, with the cursor of the next page if there is one
+func writePageJSON(w http.ResponseWriter, outgoingJSON string, next int) {
+	if next > 0 {
+		w.Header().Set(NextCursorHeader, strconv.Itoa(next))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprint(w, outgoingJSON)
+}
@@ -0,0 +1,93 @@
+package rfq
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Manager tracks quote requests by id and QuoteReqID
+type Manager struct {
+	sync.RWMutex
+	requestID int
+
+	requests         map[int]*Request
+	quoteReqIDLookup map[string]*Request
+}
+
+// NewManager returns an empty rfq manager
+func NewManager() *Manager {
+	return &Manager{
+		requests:         make(map[int]*Request),
+		quoteReqIDLookup: make(map[string]*Request),
+	}
+}
+
+// Save assigns the request an id and QuoteReqID
+func (m *Manager) Save(req *Request) error {
+	m.requestID++
+	req.ID = m.requestID
+	req.QuoteReqID = fmt.Sprintf("RFQ-%v", req.ID)
+	req.Status = Pending
+	req.Quotes = []*Quote{}
+
+	m.requests[req.ID] = req
+	m.quoteReqIDLookup[req.QuoteReqID] = req
+
+	return nil
+}
+
+// Get returns the request with id
+func (m *Manager) Get(id int) (*Request, error) {
+	req, ok := m.requests[id]
+	if !ok {
+		return nil, fmt.Errorf("could not find rfq with id %v", id)
+	}
+
+	return req, nil
+}
+
+// GetByQuoteReqID returns the request with quoteReqID
+func (m *Manager) GetByQuoteReqID(quoteReqID string) (*Request, error) {
+	req, ok := m.quoteReqIDLookup[quoteReqID]
+	if !ok {
+		return nil, fmt.Errorf("could not find rfq with quotereqid %v", quoteReqID)
+	}
+
+	return req, nil
+}
+
+// GetByQuoteID returns the request that received the quote with quoteID
+func (m *Manager) GetByQuoteID(quoteID string) (*Request, error) {
+	for _, req := range m.requests {
+		if _, err := req.GetQuote(quoteID); err == nil {
+			return req, nil
+		}
+	}
+
+	return nil, fmt.Errorf("could not find rfq with quoteid %v", quoteID)
+}
+
+// GetAll returns all requests
+func (m *Manager) GetAll() []*Request {
+	requests := make([]*Request, 0, len(m.requests))
+	for _, v := range m.requests {
+		requests = append(requests, v)
+	}
+
+	return requests
+}
+
+// AddQuote records a quote for the request, replacing any earlier quote with the same QuoteID
+func (m *Manager) AddQuote(req *Request, quote *Quote) {
+	for i, q := range req.Quotes {
+		if q.QuoteID == quote.QuoteID {
+			req.Quotes[i] = quote
+			return
+		}
+	}
+
+	req.Quotes = append(req.Quotes, quote)
+	if req.Status == Pending {
+		req.Status = Quoted
+	}
+}
@@ -0,0 +1,113 @@
+package rfq
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/quickfixgo/enum"
+	"github.com/shopspring/decimal"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+// Status is the state of a quote request
+type Status string
+
+// Quote request states
+const (
+	Pending   Status = "pending"
+	Quoted    Status = "quoted"
+	Rejected  Status = "rejected"
+	Cancelled Status = "cancelled"
+	Done      Status = "done"
+)
+
+// Request is a quote request sent to a counterparty along with the quotes received for it
+type Request struct {
+	ID              int                `json:"id"`
+	QuoteReqID      string             `json:"quote_req_id"`
+	SessionID       quickfix.SessionID `json:"-"`
+	Session         string             `json:"session_id"`
+	Symbol          string             `json:"symbol"`
+	SecurityType    enum.SecurityType  `json:"security_type"`
+	Side            enum.Side          `json:"side"`
+	Quantity        string             `json:"quantity"`
+	QuantityDecimal decimal.Decimal    `json:"-"`
+	Status          Status             `json:"status"`
+	RejectReason    string             `json:"reject_reason"`
+	Quotes          []*Quote           `json:"quotes"`
+}
+
+// Init initialized computed fields on request from user input
+func (req *Request) Init() error {
+	var err error
+	if req.Symbol == "" {
+		return errors.New("Invalid Symbol")
+	}
+
+	if req.QuantityDecimal, err = decimal.NewFromString(req.Quantity); err != nil {
+		return errors.New("Invalid Qty")
+	}
+
+	return nil
+}
+
+// GetQuote returns the quote with quoteID
+func (req *Request) GetQuote(quoteID string) (*Quote, error) {
+	for _, q := range req.Quotes {
+		if q.QuoteID == quoteID {
+			return q, nil
+		}
+	}
+
+	return nil, fmt.Errorf("could not find quote with id %v", quoteID)
+}
+
+// Refresh updates the expiry countdown of the quotes
+func (req *Request) Refresh(now time.Time) {
+	for _, q := range req.Quotes {
+		q.refresh(now)
+	}
+}
+
+// Quote is a two way price received for a request
+type Quote struct {
+	QuoteID    string    `json:"quote_id"`
+	BidPx      string    `json:"bid_px"`
+	OfferPx    string    `json:"offer_px"`
+	BidSize    string    `json:"bid_size"`
+	OfferSize  string    `json:"offer_size"`
+	ValidUntil time.Time `json:"valid_until"`
+	Received   time.Time `json:"received"`
+	Cancelled  bool      `json:"cancelled"`
+	Hit        bool      `json:"hit"`
+
+	// ExpiresIn is the number of seconds until the quote expires, -1 if the quote has no expiry
+	ExpiresIn float64 `json:"expires_in"`
+	Active    bool    `json:"active"`
+}
+
+func (q *Quote) refresh(now time.Time) {
+	q.ExpiresIn = -1
+	if !q.ValidUntil.IsZero() {
+		q.ExpiresIn = math.Max(math.Round(q.ValidUntil.Sub(now).Seconds()*10)/10, 0)
+	}
+
+	q.Active = !q.Cancelled && !q.Hit && q.ExpiresIn != 0
+}
+
+// Price returns the price at which side can trade on the quote
+func (q *Quote) Price(side enum.Side) (string, error) {
+	price := q.OfferPx
+	if side != enum.Side_BUY {
+		price = q.BidPx
+	}
+
+	if price == "" {
+		return "", fmt.Errorf("quote %v has no price for side %v", q.QuoteID, side)
+	}
+
+	return price, nil
+}
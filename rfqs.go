@@ -0,0 +1,228 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/quickfixgo/enum"
+	"github.com/quickfixgo/traderui/audit"
+	"github.com/quickfixgo/traderui/auth"
+	"github.com/quickfixgo/traderui/oms"
+	"github.com/quickfixgo/traderui/rfq"
+)
+
+type hitRequest struct {
+	Side     enum.Side `json:"side"`
+	Quantity string    `json:"quantity"`
+	Account  string    `json:"account"`
+}
+
+func (c tradeClient) RFQsAsJSON(r *http.Request) (string, error) {
+	c.rfqs.Lock()
+	defer c.rfqs.Unlock()
+
+	requests := make([]*rfq.Request, 0)
+	now := time.Now()
+	for _, req := range c.rfqs.GetAll() {
+		if auth.CanUseSession(r, req.Session) {
+			req.Refresh(now)
+			requests = append(requests, req)
+		}
+	}
+
+	b, err := json.Marshal(requests)
+	return string(b), err
+}
+
+func (c tradeClient) fetchRequestedRFQ(r *http.Request) (*rfq.Request, error) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		panic(err)
+	}
+
+	return c.rfqs.Get(id)
+}
+
+func (c tradeClient) writeRFQJSON(w http.ResponseWriter, req *rfq.Request) {
+	req.Refresh(time.Now())
+	outgoingJSON, err := json.Marshal(req)
+	if err != nil {
+		log.Printf("[ERROR] err = %+v\n", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprint(w, string(outgoingJSON))
+}
+
+func (c tradeClient) getRFQs(w http.ResponseWriter, r *http.Request) {
+	outgoingJSON, err := c.RFQsAsJSON(r)
+	if err != nil {
+		log.Printf("[ERROR] err = %+v\n", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprint(w, outgoingJSON)
+}
+
+func (c tradeClient) getRFQ(w http.ResponseWriter, r *http.Request) {
+	c.rfqs.Lock()
+	defer c.rfqs.Unlock()
+
+	req, err := c.fetchRequestedRFQ(r)
+	if err != nil || !auth.CanUseSession(r, req.Session) {
+		http.Error(w, "RFQ not found", http.StatusNotFound)
+		return
+	}
+
+	c.writeRFQJSON(w, req)
+}
+
+func (c tradeClient) newRFQ(w http.ResponseWriter, r *http.Request) {
+	var req rfq.Request
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&req)
+	if err != nil {
+		log.Printf("[ERROR] %v\n", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = auth.AuthorizeSession(r, auth.ActionNew, req.Session); err != nil {
+		log.Printf("[ERROR] %v\n", err)
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	if sessionID, ok := c.SessionIDs[req.Session]; ok {
+		req.SessionID = sessionID
+	} else {
+		log.Println("[ERROR] Invalid SessionID")
+		http.Error(w, "Invalid SessionID", http.StatusBadRequest)
+		return
+	}
+
+	if err = req.Init(); err != nil {
+		log.Printf("[ERROR] %v\n", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	c.rfqs.Lock()
+	defer c.rfqs.Unlock()
+
+	// the request is saved for its QuoteReqID and cancelled if no counterparty sees it
+	_ = c.rfqs.Save(&req)
+	msg, err := c.QuoteRequest(req)
+	if err != nil {
+		log.Printf("[ERROR] %v\n", err)
+		req.Status = rfq.Cancelled
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = c.sender.Send(msg, req.SessionID); err != nil {
+		log.Printf("[ERROR] %v\n", err)
+		req.Status = rfq.Cancelled
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	c.writeRFQJSON(w, &req)
+}
+
+// hitQuote trades on a quote by sending a previously quoted order that references its QuoteID
+func (c tradeClient) hitQuote(w http.ResponseWriter, r *http.Request) {
+	var hit hitRequest
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&hit); err != nil {
+		log.Printf("[ERROR] %v\n", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	c.rfqs.Lock()
+	defer c.rfqs.Unlock()
+
+	req, err := c.fetchRequestedRFQ(r)
+	if err != nil || !auth.CanUseSession(r, req.Session) {
+		http.Error(w, "RFQ not found", http.StatusNotFound)
+		return
+	}
+
+	quote, err := req.GetQuote(mux.Vars(r)["quote_id"])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	req.Refresh(time.Now())
+	if !quote.Active {
+		http.Error(w, "quote is no longer active", http.StatusConflict)
+		return
+	}
+
+	order := oms.Order{
+		Session:      req.Session,
+		Symbol:       req.Symbol,
+		SecurityType: req.SecurityType,
+		Side:         req.Side,
+		Quantity:     req.Quantity,
+		Account:      hit.Account,
+		User:         auth.Username(r),
+		OrdType:      enum.OrdType_PREVIOUSLY_QUOTED,
+		QuoteID:      quote.QuoteID,
+	}
+
+	if hit.Side != "" {
+		order.Side = hit.Side
+	}
+
+	if hit.Quantity != "" {
+		order.Quantity = hit.Quantity
+	}
+
+	if order.Side == "" {
+		http.Error(w, "Invalid Side", http.StatusBadRequest)
+		return
+	}
+
+	if order.Price, err = quote.Price(order.Side); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if !authorize(w, r, auth.ActionNew, order.Session, order.Account) {
+		return
+	}
+
+	if err = c.validateOrder(&order); err != nil {
+		log.Printf("[ERROR] %v\n", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = c.SendOrder(&order)
+	audit.AddClOrdIDs(r, order.ClOrdID)
+	if err != nil {
+		log.Printf("[ERROR] %v\n", err)
+		http.Error(w, err.Error(), sendStatus(err, http.StatusInternalServerError))
+		return
+	}
+
+	quote.Hit = true
+	req.Status = rfq.Done
+
+	c.RLock()
+	defer c.RUnlock()
+	c.writeOrderJSON(w, &order)
+}
@@ -0,0 +1,131 @@
+package secmaster
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/quickfixgo/enum"
+	"github.com/shopspring/decimal"
+)
+
+// Status is the trading status of a security
+type Status string
+
+const (
+	// Trading securities accept orders, the status of securities without one
+	Trading Status = "trading"
+	// Halted securities reject orders
+	Halted Status = "halted"
+)
+
+// Security is the reference data of a symbol. Zero tick and lot sizes and a missing price
+// precision are not checked. The reference price fills orders of paper sessions, zero if none.
+type Security struct {
+	Symbol            string            `json:"symbol"`
+	SecurityType      enum.SecurityType `json:"security_type"`
+	Currency          string            `json:"currency"`
+	TickSize          decimal.Decimal   `json:"tick_size"`
+	LotSize           decimal.Decimal   `json:"lot_size"`
+	PricePrecision    *int              `json:"price_precision,omitempty"`
+	Multiplier        decimal.Decimal   `json:"multiplier"`
+	MaturityMonthYear string            `json:"maturity_month_year"`
+	Status            Status            `json:"trading_status"`
+	ReferencePrice    decimal.Decimal   `json:"reference_price"`
+	UpdatedAt         time.Time         `json:"updated_at"`
+}
+
+// ParseJSON reads a json array of securities
+func ParseJSON(r io.Reader) ([]Security, error) {
+	var securities []Security
+	if err := json.NewDecoder(r).Decode(&securities); err != nil {
+		return nil, err
+	}
+
+	return securities, nil
+}
+
+// ParseCSV reads securities from CSV. The first record is a header naming the columns with the
+// json names of the security fields, e.g. symbol,security_type,currency,tick_size,lot_size
+func ParseCSV(r io.Reader) ([]Security, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("invalid csv header: %v", err)
+	}
+
+	var securities []Security
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var sec Security
+		for i, value := range record {
+			if err := setColumn(&sec, strings.TrimSpace(header[i]), strings.TrimSpace(value)); err != nil {
+				return nil, fmt.Errorf("line %v: %v", line, err)
+			}
+		}
+		securities = append(securities, sec)
+	}
+
+	return securities, nil
+}
+
+func setColumn(sec *Security, column, value string) (err error) {
+	switch column {
+	case "symbol":
+		sec.Symbol = value
+	case "security_type":
+		sec.SecurityType = enum.SecurityType(value)
+	case "currency":
+		sec.Currency = value
+	case "tick_size":
+		sec.TickSize, err = parseDecimal(column, value)
+	case "lot_size":
+		sec.LotSize, err = parseDecimal(column, value)
+	case "price_precision":
+		if value != "" {
+			precision, err := strconv.Atoi(value)
+			if err != nil {
+				return fmt.Errorf("invalid price_precision %v", value)
+			}
+			sec.PricePrecision = &precision
+		}
+	case "multiplier":
+		sec.Multiplier, err = parseDecimal(column, value)
+	case "maturity_month_year":
+		sec.MaturityMonthYear = value
+	case "trading_status":
+		sec.Status = Status(value)
+	case "reference_price":
+		sec.ReferencePrice, err = parseDecimal(column, value)
+	default:
+		return fmt.Errorf("unknown column %v", column)
+	}
+
+	return err
+}
+
+func parseDecimal(column, value string) (decimal.Decimal, error) {
+	if value == "" {
+		return decimal.Zero, nil
+	}
+
+	d, err := decimal.NewFromString(value)
+	if err != nil {
+		return d, fmt.Errorf("invalid %v %v", column, value)
+	}
+
+	return d, nil
+}
@@ -0,0 +1,77 @@
+package secmaster
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Store is the security master, reference data by symbol
+type Store struct {
+	sync.RWMutex
+	securities map[string]*Security
+}
+
+// NewStore returns an empty security master
+func NewStore() *Store {
+	return &Store{securities: make(map[string]*Security)}
+}
+
+// Load reads securities from a CSV file if its name ends in .csv, a json file otherwise
+func (s *Store) Load(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	parse := ParseJSON
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		parse = ParseCSV
+	}
+
+	securities, err := parse(f)
+	if err != nil {
+		return err
+	}
+
+	for i := range securities {
+		s.Put(securities[i])
+	}
+
+	return nil
+}
+
+// Put adds the security or replaces the one with its symbol
+func (s *Store) Put(sec Security) {
+	if sec.Status == "" {
+		sec.Status = Trading
+	}
+	sec.UpdatedAt = time.Now().UTC()
+	s.securities[sec.Symbol] = &sec
+}
+
+// Get returns the security with symbol
+func (s *Store) Get(symbol string) (*Security, bool) {
+	sec, ok := s.securities[symbol]
+	return sec, ok
+}
+
+// All returns every security in symbol order
+func (s *Store) All() []*Security {
+	securities := make([]*Security, 0, len(s.securities))
+	for _, sec := range s.securities {
+		securities = append(securities, sec)
+	}
+	sort.Slice(securities, func(i, j int) bool { return securities[i].Symbol < securities[j].Symbol })
+
+	return securities
+}
+
+// Len is the number of securities
+func (s *Store) Len() int {
+	return len(s.securities)
+}
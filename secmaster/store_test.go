@@ -0,0 +1,120 @@
+package secmaster
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/quickfixgo/enum"
+
+	"github.com/quickfixgo/traderui/oms"
+)
+
+const testCSV = `symbol,security_type,currency,tick_size,lot_size,price_precision,multiplier,maturity_month_year,trading_status,reference_price
+IBM,CS,USD,0.01,100,2,1,,,125.50
+ESZ6,FUT,USD,0.25,1,,50,202612,,
+XYZ,CS,USD,,,,,,halted,
+`
+
+func loadTestStore(t *testing.T) *Store {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "securities.csv")
+	if err := os.WriteFile(path, []byte(testCSV), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewStore()
+	if err := s.Load(path); err != nil {
+		t.Fatal(err)
+	}
+
+	return s
+}
+
+func TestLoadCSV(t *testing.T) {
+	s := loadTestStore(t)
+	if s.Len() != 3 {
+		t.Fatalf("got %v securities, want 3", s.Len())
+	}
+
+	es, ok := s.Get("ESZ6")
+	if !ok {
+		t.Fatal("ESZ6 not loaded")
+	}
+	if es.SecurityType != enum.SecurityType_FUTURE || es.TickSize.String() != "0.25" || es.Multiplier.String() != "50" ||
+		es.MaturityMonthYear != "202612" || es.PricePrecision != nil || es.Status != Trading {
+		t.Errorf("got %+v", es)
+	}
+
+	if ibm, _ := s.Get("IBM"); ibm.PricePrecision == nil || *ibm.PricePrecision != 2 || ibm.ReferencePrice.String() != "125.5" {
+		t.Errorf("got IBM price precision %v reference price %v, want 2 and 125.5", ibm.PricePrecision, ibm.ReferencePrice)
+	}
+
+	if all := s.All(); all[0].Symbol != "ESZ6" || all[2].Symbol != "XYZ" {
+		t.Errorf("got securities out of symbol order")
+	}
+
+	if _, err := ParseCSV(strings.NewReader("symbol,isin\nIBM,US4592001014\n")); err == nil {
+		t.Error("expected an error for an unknown column")
+	}
+}
+
+func TestLoadJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "securities.json")
+	data := `[{"symbol":"IBM","tick_size":"0.01","lot_size":"100","trading_status":"halted"}]`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewStore()
+	if err := s.Load(path); err != nil {
+		t.Fatal(err)
+	}
+
+	ibm, ok := s.Get("IBM")
+	if !ok || ibm.TickSize.String() != "0.01" || ibm.LotSize.String() != "100" || ibm.Status != Halted {
+		t.Errorf("got %+v", ibm)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	s := loadTestStore(t)
+
+	tests := []struct {
+		name  string
+		order oms.Order
+		valid bool
+	}{
+		{"limit", oms.Order{Symbol: "IBM", Quantity: "200", OrdType: enum.OrdType_LIMIT, Price: "101.25"}, true},
+		{"market", oms.Order{Symbol: "IBM", Quantity: "100", OrdType: enum.OrdType_MARKET}, true},
+		{"unknown symbol", oms.Order{Symbol: "MSFT", Quantity: "100", OrdType: enum.OrdType_MARKET}, false},
+		{"halted", oms.Order{Symbol: "XYZ", Quantity: "100", OrdType: enum.OrdType_MARKET}, false},
+		{"odd lot", oms.Order{Symbol: "IBM", Quantity: "150", OrdType: enum.OrdType_MARKET}, false},
+		{"off tick", oms.Order{Symbol: "ESZ6", Quantity: "1", OrdType: enum.OrdType_LIMIT, Price: "4500.10"}, false},
+		{"on tick", oms.Order{Symbol: "ESZ6", Quantity: "3", OrdType: enum.OrdType_LIMIT, Price: "4500.75"}, true},
+		{"stop off tick", oms.Order{Symbol: "ESZ6", Quantity: "1", OrdType: enum.OrdType_STOP, StopPrice: "4500.1"}, false},
+		{"trailing zero", oms.Order{Symbol: "IBM", Quantity: "100", OrdType: enum.OrdType_LIMIT, Price: "101.250"}, true},
+		{"more decimals", oms.Order{Symbol: "IBM", Quantity: "100", OrdType: enum.OrdType_LIMIT, Price: "101.255"}, false},
+		{"multileg", oms.Order{Symbol: "IBM", Quantity: "1", OrdType: enum.OrdType_MARKET, Legs: []oms.Leg{
+			{Symbol: "IBM", Side: enum.Side_BUY, Ratio: "1"}, {Symbol: "ESZ6", Side: enum.Side_SELL, Ratio: "1"},
+		}}, true},
+		{"multileg halted leg", oms.Order{Symbol: "IBM", Quantity: "1", OrdType: enum.OrdType_MARKET, Legs: []oms.Leg{
+			{Symbol: "IBM", Side: enum.Side_BUY, Ratio: "1"}, {Symbol: "XYZ", Side: enum.Side_SELL, Ratio: "1"},
+		}}, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			order := test.order
+			if err := order.Init(); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := s.Validate(&order); (err == nil) != test.valid {
+				t.Errorf("got %v, want valid %v", err, test.valid)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package secmaster
+
+import (
+	"fmt"
+
+	"github.com/quickfixgo/enum"
+	"github.com/shopspring/decimal"
+
+	"github.com/quickfixgo/traderui/oms"
+)
+
+// Validate rejects orders for unknown or halted symbols, at prices off the tick size or finer
+// than the price precision, and in odd lots. Only the symbols of multileg orders are checked.
+// order must be initialized.
+func (s *Store) Validate(order *oms.Order) error {
+	if len(order.Legs) > 0 {
+		for _, leg := range order.Legs {
+			if _, err := s.tradable(leg.Symbol); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	sec, err := s.tradable(order.Symbol)
+	if err != nil {
+		return err
+	}
+
+	switch order.OrdType {
+	case enum.OrdType_LIMIT, enum.OrdType_STOP_LIMIT, enum.OrdType_PREVIOUSLY_QUOTED:
+		if err := sec.checkPrice("Price", order.PriceDecimal); err != nil {
+			return err
+		}
+	}
+
+	switch order.OrdType {
+	case enum.OrdType_STOP, enum.OrdType_STOP_LIMIT:
+		if err := sec.checkPrice("StopPrice", order.StopPriceDecimal); err != nil {
+			return err
+		}
+	}
+
+	if !multipleOf(order.QuantityDecimal, sec.LotSize) {
+		return fmt.Errorf("Qty %v of %v is not a multiple of the lot size %v", order.QuantityDecimal, sec.Symbol, sec.LotSize)
+	}
+
+	return nil
+}
+
+func (s *Store) tradable(symbol string) (*Security, error) {
+	sec, ok := s.Get(symbol)
+	if !ok {
+		return nil, fmt.Errorf("Unknown Symbol %v", symbol)
+	}
+	if sec.Status != Trading {
+		return nil, fmt.Errorf("Symbol %v is %v", symbol, sec.Status)
+	}
+
+	return sec, nil
+}
+
+func (sec *Security) checkPrice(name string, px decimal.Decimal) error {
+	if sec.PricePrecision != nil && !px.Equal(px.Truncate(int32(*sec.PricePrecision))) {
+		return fmt.Errorf("%v %v of %v has more than %v decimals", name, px, sec.Symbol, *sec.PricePrecision)
+	}
+	if !multipleOf(px, sec.TickSize) {
+		return fmt.Errorf("%v %v of %v is not a multiple of the tick size %v", name, px, sec.Symbol, sec.TickSize)
+	}
+
+	return nil
+}
+
+// multipleOf is true if d is a multiple of size, or size is not set
+func multipleOf(d, size decimal.Decimal) bool {
+	return !size.IsPositive() || d.Mod(size).IsZero()
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+func (c tradeClient) getSecurities(w http.ResponseWriter, r *http.Request) {
+	c.securities.RLock()
+	outgoingJSON, err := json.Marshal(c.securities.All())
+	c.securities.RUnlock()
+	if err != nil {
+		log.Printf("[ERROR] err = %+v\n", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprint(w, string(outgoingJSON))
+}
+
+func (c tradeClient) getSecurity(w http.ResponseWriter, r *http.Request) {
+	c.securities.RLock()
+	defer c.securities.RUnlock()
+
+	sec, ok := c.securities.Get(mux.Vars(r)["symbol"])
+	if !ok {
+		http.Error(w, "Security not found", http.StatusNotFound)
+		return
+	}
+
+	outgoingJSON, err := json.Marshal(sec)
+	if err != nil {
+		log.Printf("[ERROR] err = %+v\n", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprint(w, string(outgoingJSON))
+}
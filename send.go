@@ -0,0 +1,154 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/quickfixgo/enum"
+	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/tag"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/quickfixgo/traderui/sender"
+)
+
+// senderConfig selects the middleware messages pass through on their way to quickfix
+type senderConfig struct {
+	// Throttle limits the order messages of sessions without throttle settings
+	Throttle sender.Limit
+	// Sessions are the limits of sessions with throttle settings
+	Sessions map[quickfix.SessionID]sender.Limit
+	// Routes send the messages of sessions without a counterparty, e.g. paper sessions
+	Routes map[quickfix.SessionID]sender.Sender
+	// DryRun logs messages instead of sending them
+	DryRun bool
+}
+
+// logDryRun logs a message not sent in a dry run
+func logDryRun(msg *quickfix.Message, sessionID quickfix.SessionID, _ error) {
+	log.Printf("[WARN] dry run, not sent to %v: %v\n", sessionID, strings.ReplaceAll(msg.String(), "\x01", "|"))
+}
+
+// throttledMsgTypes are the order messages limited by the throttle
+var throttledMsgTypes = []string{
+	string(enum.MsgType_ORDER_SINGLE),
+	string(enum.MsgType_NEW_ORDER_MULTILEG),
+	string(enum.MsgType_ORDER_LIST),
+	string(enum.MsgType_ORDER_CANCEL_REQUEST),
+	string(enum.MsgType_ORDER_CANCEL_REPLACE_REQUEST),
+}
+
+// readSessionLimits reads the ThrottleRate, ThrottleBurst, ThrottlePolicy and ThrottleBacklog
+// settings of each session, missing settings defaulting to the Throttle of the config
+func (cfg *senderConfig) readSessionLimits(settings map[quickfix.SessionID]*quickfix.SessionSettings) error {
+	cfg.Sessions = make(map[quickfix.SessionID]sender.Limit)
+	for sessionID, s := range settings {
+		if !s.HasSetting("ThrottleRate") && !s.HasSetting("ThrottleBurst") && !s.HasSetting("ThrottlePolicy") && !s.HasSetting("ThrottleBacklog") {
+			continue
+		}
+
+		limit := cfg.Throttle
+		var err error
+		if s.HasSetting("ThrottleRate") {
+			rate, _ := s.Setting("ThrottleRate")
+			if limit.Rate, err = strconv.ParseFloat(rate, 64); err != nil {
+				return fmt.Errorf("%v: ThrottleRate: %v", sessionID, err)
+			}
+		}
+		if s.HasSetting("ThrottleBurst") {
+			if limit.Burst, err = s.IntSetting("ThrottleBurst"); err != nil {
+				return fmt.Errorf("%v: %v", sessionID, err)
+			}
+		}
+		if s.HasSetting("ThrottlePolicy") {
+			policy, _ := s.Setting("ThrottlePolicy")
+			if limit.Policy, err = sender.ParsePolicy(policy); err != nil {
+				return fmt.Errorf("%v: %v", sessionID, err)
+			}
+		}
+		if s.HasSetting("ThrottleBacklog") {
+			if limit.Backlog, err = s.IntSetting("ThrottleBacklog"); err != nil {
+				return fmt.Errorf("%v: %v", sessionID, err)
+			}
+		}
+
+		cfg.Sessions[sessionID] = limit
+	}
+
+	return nil
+}
+
+// newSender returns the sender of the client and its throttle: failed sends are audited, then
+// orders are throttled, messages timed and finally routed, sent or, in a dry run, logged
+func (cfg senderConfig) newSender(c *tradeClient) (sender.Sender, *sender.Throttle) {
+	var middleware []sender.Middleware
+	if c.auditLog != nil {
+		middleware = append(middleware, sender.Audit(c.auditLog.RecordSend))
+	}
+
+	throttle := sender.NewThrottle(cfg.Throttle, cfg.Sessions, throttledMsgTypes...)
+	throttle.Failed = c.queuedSendFailed
+	middleware = append(middleware, throttle.Middleware)
+
+	middleware = append(middleware, sender.Latency(func(sessionID quickfix.SessionID, msgType string, d time.Duration) {
+		c.metrics.SendDuration(sessionID.String(), msgType, d)
+	}))
+
+	if len(cfg.Routes) > 0 {
+		middleware = append(middleware, sender.Route(cfg.Routes))
+	}
+
+	if cfg.DryRun {
+		middleware = append(middleware, sender.DryRun(logDryRun))
+	}
+
+	return sender.Chain(sender.QuickFIX, middleware...), throttle
+}
+
+// queuedSendFailed audits a throttled message that failed once sent from the queue and rejects
+// the new order it carried, whose submitter was told it was sent
+func (c *tradeClient) queuedSendFailed(msg *quickfix.Message, sessionID quickfix.SessionID, err error) {
+	log.Printf("[ERROR] queued message to %v not sent: %v\n", sessionID, err)
+	if c.auditLog != nil {
+		c.auditLog.RecordSend(msg, sessionID, err)
+	}
+
+	msgType, _ := msg.MsgType()
+	switch enum.MsgType(msgType) {
+	case enum.MsgType_ORDER_SINGLE, enum.MsgType_NEW_ORDER_MULTILEG:
+	default:
+		return
+	}
+
+	clOrdID, _ := msg.Body.GetString(tag.ClOrdID)
+	c.RLock()
+	order, err := c.GetByClOrdID(clOrdID)
+	c.RUnlock()
+	if err == nil && order.OrdStatus == "" {
+		c.rejectUnsent(order)
+	}
+}
+
+// sendStatus is the http status of a failed send, 429 if throttled and code otherwise
+func sendStatus(err error, code int) int {
+	if errors.Is(err, sender.ErrRateLimited) {
+		return http.StatusTooManyRequests
+	}
+
+	return code
+}
+
+// sendError is the grpc error of a failed send, ResourceExhausted if throttled and code otherwise
+func sendError(err error, code codes.Code) error {
+	if errors.Is(err, sender.ErrRateLimited) {
+		code = codes.ResourceExhausted
+	}
+
+	return status.Error(code, err.Error())
+}
@@ -0,0 +1,53 @@
+package sender
+
+import (
+	"time"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+// Audit calls hook with the outcome of every message
+func Audit(hook Hook) Middleware {
+	return func(next Sender) Sender {
+		return Func(func(msg quickfix.Messagable, sessionID quickfix.SessionID) error {
+			err := next.Send(msg, sessionID)
+			hook(msg.ToMessage(), sessionID, err)
+			return err
+		})
+	}
+}
+
+// Latency calls observe with the time taken to send every message
+func Latency(observe func(sessionID quickfix.SessionID, msgType string, d time.Duration)) Middleware {
+	return func(next Sender) Sender {
+		return Func(func(msg quickfix.Messagable, sessionID quickfix.SessionID) error {
+			start := time.Now()
+			err := next.Send(msg, sessionID)
+			observe(sessionID, msgType(msg.ToMessage()), time.Since(start))
+			return err
+		})
+	}
+}
+
+// DryRun hands every message to hook instead of sending it
+func DryRun(hook Hook) Middleware {
+	return func(Sender) Sender {
+		return Func(func(msg quickfix.Messagable, sessionID quickfix.SessionID) error {
+			hook(msg.ToMessage(), sessionID, nil)
+			return nil
+		})
+	}
+}
+
+// Route sends the messages of the sessions of routes through their Sender instead of next
+func Route(routes map[quickfix.SessionID]Sender) Middleware {
+	return func(next Sender) Sender {
+		return Func(func(msg quickfix.Messagable, sessionID quickfix.SessionID) error {
+			if s, ok := routes[sessionID]; ok {
+				return s.Send(msg, sessionID)
+			}
+
+			return next.Send(msg, sessionID)
+		})
+	}
+}
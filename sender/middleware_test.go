@@ -0,0 +1,96 @@
+package sender
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+var testSessionID = quickfix.SessionID{BeginString: quickfix.BeginStringFIX42, SenderCompID: "TW", TargetCompID: "ISLD"}
+
+func testMessage(msgType string) *quickfix.Message {
+	msg := quickfix.NewMessage()
+	msg.Header.SetString(35, msgType)
+	return msg
+}
+
+// counter counts the messages it is sent, failing with err
+type counter struct {
+	n   int
+	err error
+}
+
+func (c *counter) Send(quickfix.Messagable, quickfix.SessionID) error {
+	c.n++
+	return c.err
+}
+
+func TestChainOrder(t *testing.T) {
+	var order []string
+	mark := func(name string) Middleware {
+		return func(next Sender) Sender {
+			return Func(func(msg quickfix.Messagable, sessionID quickfix.SessionID) error {
+				order = append(order, name)
+				return next.Send(msg, sessionID)
+			})
+		}
+	}
+
+	s := Chain(new(counter), mark("a"), mark("b"), mark("c"))
+	if err := s.Send(testMessage("D"), testSessionID); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := len(order); got != 3 || order[0] != "a" || order[1] != "b" || order[2] != "c" {
+		t.Errorf("got order %v, want [a b c]", order)
+	}
+}
+
+func TestAuditAndLatency(t *testing.T) {
+	sendErr := errors.New("session not found")
+	var audited error
+	var observed string
+
+	s := Chain(&counter{err: sendErr},
+		Audit(func(_ *quickfix.Message, _ quickfix.SessionID, err error) { audited = err }),
+		Latency(func(_ quickfix.SessionID, msgType string, _ time.Duration) { observed = msgType }),
+	)
+
+	if err := s.Send(testMessage("F"), testSessionID); err != sendErr {
+		t.Errorf("got %v, want %v", err, sendErr)
+	}
+	if audited != sendErr || observed != "F" {
+		t.Errorf("audited %v, observed %q", audited, observed)
+	}
+}
+
+func TestDryRun(t *testing.T) {
+	next := new(counter)
+	var hooked int
+	s := Chain(next, DryRun(func(*quickfix.Message, quickfix.SessionID, error) { hooked++ }))
+
+	if err := s.Send(testMessage("D"), testSessionID); err != nil {
+		t.Fatal(err)
+	}
+	if next.n != 0 || hooked != 1 {
+		t.Errorf("sent %v, hooked %v, want 0 and 1", next.n, hooked)
+	}
+}
+
+func TestRoute(t *testing.T) {
+	next, paper := new(counter), new(counter)
+	other := testSessionID
+	other.TargetCompID = "PAPER"
+	s := Chain(next, Route(map[quickfix.SessionID]Sender{other: paper}))
+
+	for _, sessionID := range []quickfix.SessionID{testSessionID, other, other} {
+		if err := s.Send(testMessage("D"), sessionID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if next.n != 1 || paper.n != 2 {
+		t.Errorf("sent %v and routed %v, want 1 and 2", next.n, paper.n)
+	}
+}
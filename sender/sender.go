@@ -0,0 +1,44 @@
+// Package sender delivers FIX messages to their session through a chain of middleware, so
+// throttling, auditing, timing and dry runs can wrap quickfix.SendToTarget.
+package sender
+
+import (
+	"github.com/quickfixgo/quickfix"
+)
+
+// Sender delivers a message to a session
+type Sender interface {
+	Send(msg quickfix.Messagable, sessionID quickfix.SessionID) error
+}
+
+// Func adapts a function to a Sender
+type Func func(msg quickfix.Messagable, sessionID quickfix.SessionID) error
+
+// Send calls f
+func (f Func) Send(msg quickfix.Messagable, sessionID quickfix.SessionID) error {
+	return f(msg, sessionID)
+}
+
+// QuickFIX sends messages with quickfix.SendToTarget
+var QuickFIX Sender = Func(quickfix.SendToTarget)
+
+// Middleware wraps a Sender
+type Middleware func(next Sender) Sender
+
+// Chain wraps s in middleware, the first middleware seeing each message first
+func Chain(s Sender, middleware ...Middleware) Sender {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		s = middleware[i](s)
+	}
+
+	return s
+}
+
+// Hook observes a message sent, or not sent if err is set
+type Hook func(msg *quickfix.Message, sessionID quickfix.SessionID, err error)
+
+// msgType returns the MsgType of msg, empty if missing
+func msgType(msg *quickfix.Message) string {
+	t, _ := msg.MsgType()
+	return t
+}
@@ -0,0 +1,222 @@
+package sender
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+// ErrRateLimited is returned for messages exceeding the rate limit of their session
+var ErrRateLimited = errors.New("rate limit exceeded")
+
+// Policy is what a Throttle does with messages over the limit of their session
+type Policy string
+
+const (
+	// Reject fails messages over the limit with ErrRateLimited
+	Reject Policy = "reject"
+	// Queue holds messages over the limit in a backlog sent in order as they are allowed,
+	// failing them with ErrRateLimited once the backlog is full
+	Queue Policy = "queue"
+)
+
+// ParsePolicy parses a Policy, Reject if s is empty
+func ParsePolicy(s string) (Policy, error) {
+	switch p := Policy(s); p {
+	case "":
+		return Reject, nil
+	case Reject, Queue:
+		return p, nil
+	}
+
+	return "", fmt.Errorf("unknown throttle policy %q", s)
+}
+
+// Limit is the token bucket of a session, allowing Rate messages a second in bursts of up to
+// Burst. A Rate of 0 is unlimited.
+type Limit struct {
+	Rate   float64 `json:"rate"`
+	Burst  int     `json:"burst"`
+	Policy Policy  `json:"policy"`
+	// Backlog is the number of messages a Queue policy holds at most
+	Backlog int `json:"backlog"`
+}
+
+// ThrottleStatus is the state of the throttle of a session
+type ThrottleStatus struct {
+	Limit
+	Tokens   float64 `json:"tokens"`
+	Queued   int     `json:"queued"`
+	Rejected uint64  `json:"rejected"`
+}
+
+// bucket is the token bucket of a session along with the messages queued for its tokens
+type bucket struct {
+	tokens   float64
+	last     time.Time
+	queue    []pending
+	draining bool
+	rejected uint64
+}
+
+// pending is a message held for a token, to be sent to next
+type pending struct {
+	msg  quickfix.Messagable
+	next Sender
+}
+
+// Throttle limits the rate of messages of given types per session
+type Throttle struct {
+	// Failed is called with queued messages that could not be sent once allowed, which have no
+	// caller to return the error to. Failures are logged if nil.
+	Failed Hook
+
+	mu       sync.Mutex
+	limit    Limit
+	limits   map[quickfix.SessionID]Limit
+	msgTypes map[string]bool
+	buckets  map[quickfix.SessionID]*bucket
+}
+
+// NewThrottle returns a Throttle of msgTypes, limiting the sessions of limits to their Limit and
+// any other session to limit
+func NewThrottle(limit Limit, limits map[quickfix.SessionID]Limit, msgTypes ...string) *Throttle {
+	t := &Throttle{
+		limit:    limit,
+		limits:   limits,
+		msgTypes: make(map[string]bool),
+		buckets:  make(map[quickfix.SessionID]*bucket),
+	}
+	for _, m := range msgTypes {
+		t.msgTypes[m] = true
+	}
+
+	return t
+}
+
+func (t *Throttle) limitOf(sessionID quickfix.SessionID) Limit {
+	l, ok := t.limits[sessionID]
+	if !ok {
+		l = t.limit
+	}
+	l.Burst = max(l.Burst, 1)
+	if l.Policy == "" {
+		l.Policy = Reject
+	}
+
+	return l
+}
+
+// refill returns the bucket of the session with the tokens earned since it was last used
+func (t *Throttle) refill(sessionID quickfix.SessionID, l Limit, now time.Time) *bucket {
+	b, ok := t.buckets[sessionID]
+	if !ok {
+		b = &bucket{tokens: float64(l.Burst), last: now}
+		t.buckets[sessionID] = b
+	}
+
+	b.tokens = min(float64(l.Burst), b.tokens+now.Sub(b.last).Seconds()*l.Rate)
+	b.last = now
+	return b
+}
+
+// admit takes a token of the session for the message, or queues it to be sent by the session's
+// drain. queued is true if the message was queued.
+func (t *Throttle) admit(msg quickfix.Messagable, sessionID quickfix.SessionID, next Sender) (queued bool, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	l := t.limitOf(sessionID)
+	if l.Rate <= 0 {
+		return false, nil
+	}
+
+	b := t.refill(sessionID, l, time.Now())
+	switch {
+	// queued messages go first
+	case len(b.queue) == 0 && b.tokens >= 1:
+		b.tokens--
+		return false, nil
+
+	case l.Policy == Queue && len(b.queue) < l.Backlog:
+		b.queue = append(b.queue, pending{msg: msg, next: next})
+		if !b.draining {
+			b.draining = true
+			go t.drain(sessionID)
+		}
+		return true, nil
+	}
+
+	b.rejected++
+	return false, ErrRateLimited
+}
+
+// drain sends the queued messages of the session as its tokens allow, until the queue is empty
+func (t *Throttle) drain(sessionID quickfix.SessionID) {
+	for {
+		t.mu.Lock()
+		l := t.limitOf(sessionID)
+		b := t.refill(sessionID, l, time.Now())
+		if len(b.queue) == 0 {
+			b.draining = false
+			t.mu.Unlock()
+			return
+		}
+
+		if b.tokens < 1 {
+			d := time.Duration(math.Ceil((1 - b.tokens) / l.Rate * float64(time.Second)))
+			t.mu.Unlock()
+			time.Sleep(d)
+			continue
+		}
+
+		b.tokens--
+		p := b.queue[0]
+		b.queue = b.queue[1:]
+		t.mu.Unlock()
+
+		if err := p.next.Send(p.msg, sessionID); err != nil {
+			if t.Failed != nil {
+				t.Failed(p.msg.ToMessage(), sessionID, err)
+			} else {
+				log.Printf("[ERROR] queued %v to %v not sent: %v\n", msgType(p.msg.ToMessage()), sessionID, err)
+			}
+		}
+	}
+}
+
+// Middleware throttles the messages of the Throttle's types. Queued messages are sent in the
+// background, Send returns once they are queued.
+func (t *Throttle) Middleware(next Sender) Sender {
+	return Func(func(msg quickfix.Messagable, sessionID quickfix.SessionID) error {
+		if !t.msgTypes[msgType(msg.ToMessage())] {
+			return next.Send(msg, sessionID)
+		}
+
+		queued, err := t.admit(msg, sessionID, next)
+		if err != nil || queued {
+			return err
+		}
+
+		return next.Send(msg, sessionID)
+	})
+}
+
+// Status returns the state of the throttle of the session, false if the session is unlimited
+func (t *Throttle) Status(sessionID quickfix.SessionID) (ThrottleStatus, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	l := t.limitOf(sessionID)
+	if l.Rate <= 0 {
+		return ThrottleStatus{}, false
+	}
+
+	b := t.refill(sessionID, l, time.Now())
+	return ThrottleStatus{Limit: l, Tokens: b.tokens, Queued: len(b.queue), Rejected: b.rejected}, true
+}
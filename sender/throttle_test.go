@@ -0,0 +1,144 @@
+package sender
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+func TestThrottleReject(t *testing.T) {
+	next := new(counter)
+	throttle := NewThrottle(Limit{Rate: 1, Burst: 2, Policy: Reject}, nil, "D", "F", "G")
+	s := Chain(next, throttle.Middleware)
+
+	other := testSessionID
+	other.TargetCompID = "OTHER"
+
+	for i, want := range []error{nil, nil, ErrRateLimited} {
+		if err := s.Send(testMessage("D"), testSessionID); !errors.Is(err, want) {
+			t.Errorf("message %v: got %v, want %v", i, err, want)
+		}
+	}
+	if err := s.Send(testMessage("R"), testSessionID); err != nil {
+		t.Errorf("quote request: got %v, only orders are throttled", err)
+	}
+	if err := s.Send(testMessage("F"), other); err != nil {
+		t.Errorf("other session: got %v, sessions are limited separately", err)
+	}
+	if next.n != 4 {
+		t.Errorf("sent %v messages, want 4", next.n)
+	}
+
+	status, ok := throttle.Status(testSessionID)
+	if !ok || status.Rejected != 1 || status.Tokens >= 1 {
+		t.Errorf("got status %+v, want 1 rejected and no token left", status)
+	}
+}
+
+// chanSender hands the MsgType of every message sent to a channel, failing with err
+type chanSender struct {
+	sent chan string
+	err  error
+}
+
+func (c chanSender) Send(msg quickfix.Messagable, _ quickfix.SessionID) error {
+	c.sent <- msgType(msg.ToMessage())
+	return c.err
+}
+
+func (c chanSender) receive(t *testing.T) string {
+	t.Helper()
+
+	select {
+	case m := <-c.sent:
+		return m
+	case <-time.After(time.Second):
+		t.Fatal("queued message not sent")
+	}
+	return ""
+}
+
+func TestThrottleQueue(t *testing.T) {
+	next := chanSender{sent: make(chan string, 4)}
+	throttle := NewThrottle(Limit{Rate: 20, Burst: 1, Policy: Queue, Backlog: 2}, nil, "D", "F")
+	s := Chain(next, throttle.Middleware)
+
+	for i, m := range []string{"D", "F", "D"} {
+		if err := s.Send(testMessage(m), testSessionID); err != nil {
+			t.Fatalf("message %v: got %v", i, err)
+		}
+	}
+	if m := next.receive(t); m != "D" {
+		t.Errorf("got %v sent first, want D", m)
+	}
+	if status, _ := throttle.Status(testSessionID); status.Queued != 2 {
+		t.Errorf("got %v queued, want the messages over the rate queued without waiting", status.Queued)
+	}
+
+	if err := s.Send(testMessage("D"), testSessionID); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("got %v with a full backlog, want %v", err, ErrRateLimited)
+	}
+
+	if m1, m2 := next.receive(t), next.receive(t); m1 != "F" || m2 != "D" {
+		t.Errorf("sent %v %v from the queue, want F D in order", m1, m2)
+	}
+}
+
+func TestThrottleQueueFailed(t *testing.T) {
+	next := chanSender{sent: make(chan string, 2), err: errors.New("not logged on")}
+	throttle := NewThrottle(Limit{Rate: 50, Burst: 1, Policy: Queue, Backlog: 1}, nil, "D")
+	failed := make(chan error, 1)
+	throttle.Failed = func(msg *quickfix.Message, sessionID quickfix.SessionID, err error) { failed <- err }
+	s := Chain(next, throttle.Middleware)
+
+	if err := s.Send(testMessage("D"), testSessionID); err == nil {
+		t.Error("expected the error of a message sent at once")
+	}
+	if err := s.Send(testMessage("D"), testSessionID); err != nil {
+		t.Fatalf("queued message: got %v", err)
+	}
+
+	next.receive(t)
+	next.receive(t)
+	select {
+	case err := <-failed:
+		if err != next.err {
+			t.Errorf("got %v, want %v", err, next.err)
+		}
+	case <-time.After(time.Second):
+		t.Error("failure of the queued message not reported")
+	}
+}
+
+func TestThrottleSessionLimits(t *testing.T) {
+	unlimited := testSessionID
+	unlimited.TargetCompID = "UNLIMITED"
+
+	throttle := NewThrottle(Limit{Rate: 1}, map[quickfix.SessionID]Limit{unlimited: {}}, "D")
+	s := Chain(new(counter), throttle.Middleware)
+
+	for i := 0; i < 3; i++ {
+		if err := s.Send(testMessage("D"), unlimited); err != nil {
+			t.Errorf("message %v: got %v", i, err)
+		}
+	}
+	if _, ok := throttle.Status(unlimited); ok {
+		t.Error("got a status of an unlimited session")
+	}
+	if status, ok := throttle.Status(testSessionID); !ok || status.Burst != 1 || status.Policy != Reject {
+		t.Errorf("got status %+v of the default limit, want a burst of 1 rejecting", status)
+	}
+}
+
+func TestParsePolicy(t *testing.T) {
+	for s, want := range map[string]Policy{"": Reject, "reject": Reject, "queue": Queue} {
+		if got, err := ParsePolicy(s); err != nil || got != want {
+			t.Errorf("%q: got %v %v, want %v", s, got, err, want)
+		}
+	}
+	if _, err := ParsePolicy("drop"); err == nil {
+		t.Error("expected an error")
+	}
+}
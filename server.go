@@ -0,0 +1,107 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+)
+
+// serverConfig configures the http server of the ui
+type serverConfig struct {
+	Addr string
+
+	// CertFile and KeyFile enable TLS
+	CertFile string
+	KeyFile  string
+	// ClientCAFile enables mutual TLS, requiring client certificates signed by these CAs
+	ClientCAFile string
+
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+	IdleTimeout  time.Duration
+}
+
+// TLS is true if the server is configured to serve https
+func (cfg serverConfig) TLS() bool {
+	return cfg.CertFile != ""
+}
+
+func (cfg serverConfig) newServer(handler http.Handler) (*http.Server, error) {
+	tlsConfig, err := cfg.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Server{
+		Addr:         cfg.Addr,
+		Handler:      handler,
+		ReadTimeout:  cfg.ReadTimeout,
+		WriteTimeout: cfg.WriteTimeout,
+		IdleTimeout:  cfg.IdleTimeout,
+		TLSConfig:    tlsConfig,
+	}, nil
+}
+
+// tlsConfig loads the certificate and client CAs, nil if TLS is disabled
+func (cfg serverConfig) tlsConfig() (*tls.Config, error) {
+	if (cfg.CertFile == "") != (cfg.KeyFile == "") {
+		return nil, errors.New("TLS needs both a certificate and a key")
+	}
+
+	if !cfg.TLS() {
+		if cfg.ClientCAFile != "" {
+			return nil, errors.New("client certificate verification needs TLS")
+		}
+		return nil, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	if cfg.ClientCAFile == "" {
+		return tlsConfig, nil
+	}
+
+	pem, err := os.ReadFile(cfg.ClientCAFile)
+	if err != nil {
+		return nil, err
+	}
+
+	clientCAs := x509.NewCertPool()
+	if !clientCAs.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificates found in %v", cfg.ClientCAFile)
+	}
+
+	tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	tlsConfig.ClientCAs = clientCAs
+
+	return tlsConfig, nil
+}
+
+// serve blocks serving http or https until the server is shut down
+func (cfg serverConfig) serve(server *http.Server) error {
+	var err error
+	if cfg.TLS() {
+		// the certificate is already loaded in the TLSConfig
+		err = server.ListenAndServeTLS("", "")
+	} else {
+		err = server.ListenAndServe()
+	}
+
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+
+	return err
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/quickfixgo/traderui/auth"
+	"github.com/quickfixgo/traderui/oms"
+)
+
+// canUseTemplate is true if the user of the request owns the template or is an admin
+func canUseTemplate(r *http.Request, t *oms.Template) bool {
+	return auth.CanModify(r, t.User)
+}
+
+func (c tradeClient) TemplatesAsJSON(r *http.Request) (string, error) {
+	c.RLock()
+	defer c.RUnlock()
+
+	templates := make([]*oms.Template, 0)
+	for _, t := range c.GetTemplates() {
+		if canUseTemplate(r, t) {
+			templates = append(templates, t)
+		}
+	}
+
+	b, err := json.Marshal(templates)
+	return string(b), err
+}
+
+// fetchRequestedTemplate returns the template of the request if its user may use it
+func (c tradeClient) fetchRequestedTemplate(r *http.Request) (*oms.Template, error) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		panic(err)
+	}
+
+	t, err := c.GetTemplate(id)
+	if err != nil || !canUseTemplate(r, t) {
+		return nil, errors.New("Template not found")
+	}
+
+	return t, nil
+}
+
+func (c tradeClient) writeTemplateJSON(w http.ResponseWriter, t *oms.Template) {
+	outgoingJSON, err := json.Marshal(t)
+	if err != nil {
+		log.Printf("[ERROR] err = %+v\n", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprint(w, string(outgoingJSON))
+}
+
+// decodeTemplate reads a template from the request body
+func decodeTemplate(r *http.Request) (oms.Template, error) {
+	var t oms.Template
+	if err := json.NewDecoder(r.Body).Decode(&t); err != nil {
+		return t, err
+	}
+
+	return t, t.Init()
+}
+
+func (c tradeClient) getTemplates(w http.ResponseWriter, r *http.Request) {
+	outgoingJSON, err := c.TemplatesAsJSON(r)
+	if err != nil {
+		log.Printf("[ERROR] err = %+v\n", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprint(w, outgoingJSON)
+}
+
+func (c tradeClient) getTemplate(w http.ResponseWriter, r *http.Request) {
+	c.RLock()
+	defer c.RUnlock()
+
+	t, err := c.fetchRequestedTemplate(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	c.writeTemplateJSON(w, t)
+}
+
+func (c tradeClient) newTemplate(w http.ResponseWriter, r *http.Request) {
+	t, err := decodeTemplate(r)
+	if err != nil {
+		log.Printf("[ERROR] %v\n", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	t.User = auth.Username(r)
+
+	c.Lock()
+	defer c.Unlock()
+
+	_ = c.SaveTemplate(&t)
+	c.writeTemplateJSON(w, &t)
+}
+
+// updateTemplate replaces the name and order of a template
+func (c tradeClient) updateTemplate(w http.ResponseWriter, r *http.Request) {
+	update, err := decodeTemplate(r)
+	if err != nil {
+		log.Printf("[ERROR] %v\n", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	t, err := c.fetchRequestedTemplate(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	if t, err = c.UpdateTemplate(t.ID, update); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	c.writeTemplateJSON(w, t)
+}
+
+func (c tradeClient) deleteTemplate(w http.ResponseWriter, r *http.Request) {
+	c.Lock()
+	defer c.Unlock()
+
+	t, err := c.fetchRequestedTemplate(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	_ = c.DeleteTemplate(t.ID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// submitTemplate sends a new order from a template, overridden by any order fields in the body,
+// which is validated like any other new order
+func (c tradeClient) submitTemplate(w http.ResponseWriter, r *http.Request) {
+	c.RLock()
+	t, err := c.fetchRequestedTemplate(r)
+	var order oms.Order
+	if err == nil {
+		order = t.Order
+		order.Legs = append([]oms.Leg(nil), t.Order.Legs...)
+	}
+	c.RUnlock()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	if err = json.NewDecoder(r.Body).Decode(&order); err != nil && err != io.EOF {
+		log.Printf("[ERROR] %v\n", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	c.submitOrder(w, r, &order)
+}
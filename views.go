@@ -0,0 +1,59 @@
+package main
+
+import (
+	"embed"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"text/template"
+)
+
+//go:embed tmpl assets
+var embedded embed.FS
+
+// views renders the templates and serves the static assets, embedded in the binary or, in dev
+// mode, read from the working directory on every request for live editing
+type views struct {
+	fs        fs.FS
+	dev       bool
+	templates *template.Template
+}
+
+func newViews(dev bool) (*views, error) {
+	v := &views{fs: embedded, dev: dev}
+	if dev {
+		v.fs = os.DirFS(".")
+	}
+
+	var err error
+	v.templates, err = v.parse()
+	return v, err
+}
+
+func (v *views) parse() (*template.Template, error) {
+	return template.New("traderui").ParseFS(v.fs, "tmpl/*.html")
+}
+
+// execute renders the template with name
+func (v *views) execute(w io.Writer, name string, data interface{}) error {
+	templates := v.templates
+	if v.dev {
+		var err error
+		if templates, err = v.parse(); err != nil {
+			return err
+		}
+	}
+
+	return templates.ExecuteTemplate(w, name, data)
+}
+
+// assets serves the files under assets/
+func (v *views) assets() http.Handler {
+	assets, err := fs.Sub(v.fs, "assets")
+	if err != nil {
+		panic(err)
+	}
+
+	return http.FileServer(http.FS(assets))
+}